@@ -39,7 +39,9 @@ export function HostProvider({ children }: { children: ReactNode }) {
 
     // Connect to host SSE for real-time updates
     console.log("[HostContext] Connecting to host SSE stream:", `${getApiRoot()}/host/stream`)
-    const eventSource = new EventSource(`${getApiRoot()}/host/stream`)
+    const eventSource = new EventSource(`${getApiRoot()}/host/stream`, {
+      withCredentials: true,
+    })
 
     eventSource.onopen = () => {
       console.log("[HostContext] Host SSE connection opened")
@@ -0,0 +1,3 @@
+-- Remove timer pause state
+ALTER TABLE timers DROP COLUMN IF EXISTS remaining_ms;
+ALTER TABLE timers DROP COLUMN IF EXISTS paused_at;
\ No newline at end of file
@@ -0,0 +1 @@
+-- No seed data for timer pause state
\ No newline at end of file
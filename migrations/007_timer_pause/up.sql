@@ -0,0 +1,6 @@
+-- Timer pause state
+
+ALTER TABLE timers ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE timers ADD COLUMN IF NOT EXISTS remaining_ms BIGINT; -- Remaining duration in milliseconds while paused
+
+COMMENT ON COLUMN timers.remaining_ms IS 'Remaining duration in milliseconds, only set while the timer is paused';
\ No newline at end of file
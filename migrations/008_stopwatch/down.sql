@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS timer_results CASCADE;
+DROP TABLE IF EXISTS timer_laps CASCADE;
+ALTER TABLE timers DROP COLUMN IF EXISTS mode;
\ No newline at end of file
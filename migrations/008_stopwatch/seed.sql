@@ -0,0 +1 @@
+-- No seed data for stopwatch timers
\ No newline at end of file
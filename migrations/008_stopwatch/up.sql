@@ -0,0 +1,34 @@
+-- Stopwatch timers
+
+ALTER TABLE timers ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'countdown';
+
+CREATE TABLE IF NOT EXISTS timer_laps
+(
+    id          VARCHAR(10) PRIMARY KEY,
+    timer_id    VARCHAR(10) REFERENCES timers (id) ON DELETE CASCADE NOT NULL,
+    show_id     VARCHAR(10) REFERENCES shows (id) ON DELETE CASCADE,
+    label       VARCHAR(200),
+    elapsed_ms  BIGINT                                              NOT NULL,
+    recorded_by VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_timer_laps_timer_id ON timer_laps (timer_id);
+
+CREATE TABLE IF NOT EXISTS timer_results
+(
+    id         VARCHAR(10) PRIMARY KEY,
+    timer_id   VARCHAR(10) REFERENCES timers (id) ON DELETE CASCADE NOT NULL,
+    show_id    VARCHAR(10) REFERENCES shows (id) ON DELETE CASCADE,
+    title      VARCHAR(200)                                        NOT NULL,
+    elapsed_ms BIGINT                                              NOT NULL,
+    laps       INTEGER                                             NOT NULL DEFAULT 0,
+    stopped_by VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_timer_results_show_id ON timer_results (show_id);
+CREATE INDEX IF NOT EXISTS idx_timer_results_timer_id ON timer_results (timer_id);
+
+COMMENT ON TABLE timer_laps IS 'Lap markers recorded on stopwatch timers';
+COMMENT ON TABLE timer_results IS 'Final elapsed values of stopwatch timers, recorded per show';
@@ -0,0 +1,4 @@
+-- Revoke timer management from hosts
+UPDATE players
+SET permissions = permissions & ~524288::BIGINT
+WHERE permissions & 512 <> 0;
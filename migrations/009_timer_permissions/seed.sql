@@ -0,0 +1 @@
+-- No seed data for timer permissions
\ No newline at end of file
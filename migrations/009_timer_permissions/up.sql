@@ -0,0 +1,7 @@
+-- Grant timer management to existing hosts
+
+-- can_host is bit 9 (512), can_manage_timers is bit 19 (524288)
+UPDATE players
+SET permissions = permissions | 524288
+WHERE permissions & 512 <> 0
+  AND deleted_at IS NULL;
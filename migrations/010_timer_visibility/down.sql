@@ -0,0 +1,2 @@
+-- Remove timer visibility
+ALTER TABLE timers DROP COLUMN IF EXISTS visibility;
\ No newline at end of file
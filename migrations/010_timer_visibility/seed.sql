@@ -0,0 +1 @@
+-- No seed data for timer visibility
\ No newline at end of file
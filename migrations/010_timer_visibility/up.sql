@@ -0,0 +1,5 @@
+-- Timer visibility
+
+ALTER TABLE timers ADD COLUMN IF NOT EXISTS visibility VARCHAR(10) NOT NULL DEFAULT 'public';
+
+COMMENT ON COLUMN timers.visibility IS 'public timers are broadcast to chat and host streams, host timers only to the host stream';
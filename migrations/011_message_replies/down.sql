@@ -0,0 +1,2 @@
+-- Remove message reply index
+DROP INDEX IF EXISTS idx_messages_replying;
\ No newline at end of file
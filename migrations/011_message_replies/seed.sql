@@ -0,0 +1 @@
+-- No seed data for message replies
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Message replies
+
+CREATE INDEX IF NOT EXISTS idx_messages_replying ON messages (replying);
@@ -0,0 +1,3 @@
+-- Remove message deletion audit
+ALTER TABLE messages DROP COLUMN IF EXISTS delete_reason;
+ALTER TABLE messages DROP COLUMN IF EXISTS deleted_by;
\ No newline at end of file
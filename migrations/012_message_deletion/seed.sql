@@ -0,0 +1 @@
+-- No seed data for message deletion
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Message deletion audit
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_by VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS delete_reason TEXT;
+
+COMMENT ON COLUMN messages.deleted_by IS 'Player who deleted the message, either its author or a moderator';
@@ -0,0 +1,3 @@
+-- Remove message revisions
+DROP TABLE IF EXISTS message_revisions;
+ALTER TABLE messages DROP COLUMN IF EXISTS edited_at;
\ No newline at end of file
@@ -0,0 +1 @@
+-- No seed data for message revisions
\ No newline at end of file
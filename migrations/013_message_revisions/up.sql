@@ -0,0 +1,16 @@
+-- Message revisions
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS message_revisions
+(
+    id         VARCHAR(10) PRIMARY KEY,
+    message_id VARCHAR(10) REFERENCES messages (id) ON DELETE CASCADE NOT NULL,
+    contents   TEXT                                                 NOT NULL,
+    edited_by  VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_revisions_message_id ON message_revisions (message_id);
+
+COMMENT ON TABLE message_revisions IS 'Previous contents of edited chat messages, one row per edit';
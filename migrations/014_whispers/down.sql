@@ -0,0 +1,3 @@
+-- Remove whispers and player blocks
+DROP TABLE IF EXISTS player_blocks;
+DROP TABLE IF EXISTS whispers;
\ No newline at end of file
@@ -0,0 +1 @@
+-- No seed data for whispers
\ No newline at end of file
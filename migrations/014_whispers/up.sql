@@ -0,0 +1,29 @@
+-- Whispers and player blocks
+
+CREATE TABLE IF NOT EXISTS whispers
+(
+    id           VARCHAR(10) PRIMARY KEY,
+    show_id      VARCHAR(10) REFERENCES shows (id) ON DELETE CASCADE,
+    sender_id    VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    recipient_id VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    contents     TEXT                                                 NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_whispers_sender_id ON whispers (sender_id);
+CREATE INDEX IF NOT EXISTS idx_whispers_recipient_id ON whispers (recipient_id);
+
+COMMENT ON TABLE whispers IS 'Private messages between two players, kept apart from public chat';
+
+CREATE TABLE IF NOT EXISTS player_blocks
+(
+    player_id  VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    blocked_id VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (player_id, blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_blocks_blocked_id ON player_blocks (blocked_id);
+
+COMMENT ON TABLE player_blocks IS 'Players who have blocked each other, blocked players cannot whisper the blocker';
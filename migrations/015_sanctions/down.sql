@@ -0,0 +1,2 @@
+-- Remove player sanctions
+DROP TABLE IF EXISTS sanctions;
\ No newline at end of file
@@ -0,0 +1 @@
+-- No seed data for sanctions
\ No newline at end of file
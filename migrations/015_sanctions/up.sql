@@ -0,0 +1,21 @@
+-- Player sanctions
+
+CREATE TABLE IF NOT EXISTS sanctions
+(
+    id         VARCHAR(10) PRIMARY KEY,
+    player_id  VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    type       VARCHAR(10)                                          NOT NULL,
+    scope      VARCHAR(20)                                          NOT NULL DEFAULT 'global',
+    reason     TEXT,
+    issued_by  VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT sanctions_type_check CHECK (type IN ('mute', 'kick', 'ban')),
+    CONSTRAINT sanctions_scope_check CHECK (scope IN ('chat', 'suggestions', 'global'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_sanctions_player_id ON sanctions (player_id);
+
+COMMENT ON TABLE sanctions IS 'Mutes, kicks and bans issued by moderators, active until expires_at or revoked_at';
@@ -0,0 +1,3 @@
+-- Remove per-show chat settings
+DROP INDEX IF EXISTS idx_messages_show_player_created;
+DROP TABLE IF EXISTS show_chat_settings;
\ No newline at end of file
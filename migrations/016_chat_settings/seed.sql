@@ -0,0 +1 @@
+-- No seed data for chat settings
\ No newline at end of file
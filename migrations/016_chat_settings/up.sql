@@ -0,0 +1,14 @@
+-- Per-show chat settings
+
+CREATE TABLE IF NOT EXISTS show_chat_settings
+(
+    show_id                 VARCHAR(10) PRIMARY KEY REFERENCES shows (id) ON DELETE CASCADE,
+    slow_mode_seconds       INTEGER NOT NULL DEFAULT 0,
+    min_account_age_minutes INTEGER NOT NULL DEFAULT 0,
+    updated_by              VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    updated_at              TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_show_player_created ON messages (show_id, player_id, created_at DESC);
+
+COMMENT ON TABLE show_chat_settings IS 'Chat restrictions set by hosts for a show, such as slow mode';
@@ -0,0 +1,3 @@
+-- Remove message mentions
+DROP INDEX IF EXISTS idx_messages_mentions;
+ALTER TABLE messages DROP COLUMN IF EXISTS mentions;
\ No newline at end of file
@@ -0,0 +1 @@
+-- No seed data for message mentions
\ No newline at end of file
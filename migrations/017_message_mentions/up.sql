@@ -0,0 +1,7 @@
+-- Message mentions
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS mentions VARCHAR(10)[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_messages_mentions ON messages USING GIN (mentions);
+
+COMMENT ON COLUMN messages.mentions IS 'IDs of the players mentioned with @display_name in the message';
@@ -0,0 +1,2 @@
+-- Remove message reactions
+DROP TABLE IF EXISTS message_reactions;
\ No newline at end of file
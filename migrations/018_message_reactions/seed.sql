@@ -0,0 +1 @@
+-- No seed data for message reactions
\ No newline at end of file
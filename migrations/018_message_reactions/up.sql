@@ -0,0 +1,12 @@
+-- Message reactions
+
+CREATE TABLE IF NOT EXISTS message_reactions
+(
+    message_id VARCHAR(10) REFERENCES messages (id) ON DELETE CASCADE NOT NULL,
+    player_id  VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE  NOT NULL,
+    emoji      VARCHAR(16)                                           NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, player_id, emoji)
+);
+
+COMMENT ON TABLE message_reactions IS 'Emoji reactions players have added to chat messages, one row per player and emoji';
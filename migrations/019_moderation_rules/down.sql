@@ -0,0 +1,4 @@
+-- Remove moderation rules
+DROP TABLE IF EXISTS moderation_rule_hits;
+DROP TABLE IF EXISTS moderation_thresholds;
+DROP TABLE IF EXISTS moderation_rules;
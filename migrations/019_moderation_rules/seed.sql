@@ -0,0 +1,64 @@
+-- Default blocked terms, matching the built-in list used before the rules are loaded
+
+INSERT INTO moderation_rules (id, kind, pattern, rule_id, reason)
+VALUES
+    ('mr85fe8de4', 'term', 'nigger', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrf8a17e95', 'term', 'nigga', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr0c8615a0', 'term', 'sandnigger', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr38cbc7bb', 'term', 'coon', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr926dee39', 'term', 'chink', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrd64a57b0', 'term', 'gook', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr21554666', 'term', 'spic', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr3852a126', 'term', 'wetback', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr7c8a961e', 'term', 'beaner', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr3844f115', 'term', 'kike', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr73ba593c', 'term', 'heeb', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr17544c65', 'term', 'raghead', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr28ddedf2', 'term', 'towelhead', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr71bcdde6', 'term', 'paki', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr798b59e7', 'term', 'currymuncher', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr495eb2da', 'term', 'jap', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrf64c7429', 'term', 'slope', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr9fc3d224', 'term', 'zipperhead', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr0391a1e5', 'term', 'faggot', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrac04b70e', 'term', 'fag', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrb79a328d', 'term', 'phaggot', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr84f11608', 'term', 'phag', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrc71230fc', 'term', 'homo', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrc8645c4a', 'term', 'queer', 'keyword.slur', 'Slurs are not allowed'),
+    ('mrf9c83908', 'term', 'tranny', 'keyword.slur', 'Slurs are not allowed'),
+    ('mr64507d68', 'term', 'fuck*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mrd58036e3', 'term', 'motherfuck*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mrbca48180', 'term', 'motherfuk*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr6166ba2b', 'term', 'shit*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr45c8bd51', 'term', 'cunt*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr64c675c7', 'term', 'whore*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr9bd9a86a', 'term', 'slut*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr331361fb', 'term', 'bitch*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr35ed5406', 'term', 'pussy', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr703f115e', 'term', 'pussies', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr39f6f953', 'term', 'dick', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr266f83d2', 'term', 'cock', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mrb100bdec', 'term', 'cocksuck*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr4f44ff64', 'term', 'cocksuk*', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr819d7c15', 'term', 'asshole', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mrf73127d7', 'term', 'bastard', 'keyword.profanity', 'Profanity is not allowed'),
+    ('mr2e340581', 'term', 'racist', 'keyword.hate', 'Hateful language is not allowed'),
+    ('mr03913c54', 'term', 'nazi', 'keyword.hate', 'Hateful language is not allowed'),
+    ('mrcecafb4d', 'term', 'hitler', 'keyword.hate', 'Hateful language is not allowed'),
+    ('mr1c93b2a4', 'term', 'supremacist', 'keyword.hate', 'Hateful language is not allowed'),
+    ('mrb521792c', 'term', 'terrorist', 'keyword.hate', 'Hateful language is not allowed'),
+    ('mr4b3bca0d', 'term', 'pedophile', 'keyword.hate', 'Hateful language is not allowed'),
+    ('mr1ac5f681', 'term', 'rapist', 'keyword.hate', 'Hateful language is not allowed'),
+    ('mrfbca8844', 'term', 'murderer', 'keyword.violence', 'Violent language is not allowed'),
+    ('mr59033478', 'term', 'killer', 'keyword.violence', 'Violent language is not allowed'),
+    ('mrb6928c29', 'term', 'suicide', 'keyword.violence', 'Violent language is not allowed'),
+    ('mr0661f709', 'term', 'kill yourself', 'keyword.violence', 'Violent language is not allowed'),
+    ('mrbcbf1723', 'term', 'kys', 'keyword.violence', 'Violent language is not allowed'),
+    ('mr8f595011', 'term', 'go die', 'keyword.violence', 'Violent language is not allowed'),
+    ('mr3e71c0a4', 'term', 'die in a fire', 'keyword.violence', 'Violent language is not allowed')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO moderation_thresholds (id)
+VALUES (TRUE)
+ON CONFLICT (id) DO NOTHING;
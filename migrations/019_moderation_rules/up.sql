@@ -0,0 +1,41 @@
+-- Moderation rules managed by moderators
+
+CREATE TABLE IF NOT EXISTS moderation_rules
+(
+    id         VARCHAR(10) PRIMARY KEY,
+    kind       VARCHAR(10) NOT NULL,
+    pattern    TEXT        NOT NULL,
+    rule_id    VARCHAR(64) NOT NULL,
+    reason     TEXT,
+    enabled    BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT moderation_rules_kind_check CHECK (kind IN ('term', 'regex', 'allow'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_rules_kind ON moderation_rules (kind);
+
+CREATE TABLE IF NOT EXISTS moderation_thresholds
+(
+    id              BOOLEAN PRIMARY KEY DEFAULT TRUE,
+    caps_ratio      DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    caps_min_length INTEGER          NOT NULL DEFAULT 10,
+    repeat_limit    INTEGER          NOT NULL DEFAULT 5,
+    updated_by      VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT moderation_thresholds_single_row CHECK (id)
+);
+
+CREATE TABLE IF NOT EXISTS moderation_rule_hits
+(
+    rule_id     VARCHAR(64) NOT NULL,
+    match       TEXT        NOT NULL DEFAULT '',
+    hits        BIGINT      NOT NULL DEFAULT 0,
+    last_hit_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (rule_id, match)
+);
+
+COMMENT ON TABLE moderation_rules IS 'Blocked terms, regex rules and allowlisted phrases loaded into the moderation pipeline';
+COMMENT ON TABLE moderation_thresholds IS 'Single row holding the caps and repeated character limits of the moderation pipeline';
+COMMENT ON TABLE moderation_rule_hits IS 'How often each moderation rule rejected a message, for reviewing false positives';
@@ -0,0 +1,3 @@
+-- Remove player reports
+DROP TABLE IF EXISTS reports;
+ALTER TABLE messages DROP COLUMN IF EXISTS hidden_at;
@@ -0,0 +1 @@
+-- No seed data for reports
\ No newline at end of file
@@ -0,0 +1,33 @@
+-- Player reports and messages hidden pending review
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS reports
+(
+    id          VARCHAR(10) PRIMARY KEY,
+    reporter_id VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    player_id   VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    message_id  VARCHAR(10) REFERENCES messages (id) ON DELETE CASCADE,
+    category    VARCHAR(20)                                          NOT NULL,
+    details     TEXT,
+    status      VARCHAR(10)                                          NOT NULL DEFAULT 'open',
+    action      VARCHAR(10),
+    sanction_id VARCHAR(10) REFERENCES sanctions (id) ON DELETE SET NULL,
+    resolved_by VARCHAR(10) REFERENCES players (id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT reports_category_check CHECK (category IN ('spam', 'harassment', 'hate', 'sexual', 'spoilers', 'other')),
+    CONSTRAINT reports_status_check CHECK (status IN ('open', 'resolved')),
+    CONSTRAINT reports_action_check CHECK (action IN ('dismiss', 'delete', 'mute', 'kick', 'ban'))
+);
+
+-- A player can only have one open report against the same message or player
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_target
+    ON reports (reporter_id, player_id, COALESCE(message_id, ''))
+    WHERE status = 'open';
+
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports (status, created_at);
+
+COMMENT ON TABLE reports IS 'Reports of messages and players filed by players, reviewed by moderators';
+COMMENT ON COLUMN messages.hidden_at IS 'Set when reports hide a message from chat until a moderator reviews it';
@@ -0,0 +1,3 @@
+-- Remove the audit log
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
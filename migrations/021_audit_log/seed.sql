@@ -0,0 +1 @@
+-- No seed data for the audit log
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- Append-only log of privileged actions
+
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id          VARCHAR(10) PRIMARY KEY,
+    actor_id    VARCHAR(10),
+    action      VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id   VARCHAR(64),
+    before      JSONB,
+    after       JSONB,
+    method      VARCHAR(10),
+    path        TEXT,
+    ip          VARCHAR(64),
+    user_agent  TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log (target_type, target_id);
+
+-- Entries can be added but never changed or removed
+CREATE OR REPLACE FUNCTION audit_log_append_only()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION audit_log_append_only();
+
+COMMENT ON TABLE audit_log IS 'Privileged actions taken by hosts, moderators and admins, with the state before and after';
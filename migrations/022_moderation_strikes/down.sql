@@ -0,0 +1,2 @@
+-- Remove moderation strikes
+DROP TABLE IF EXISTS moderation_strikes;
@@ -0,0 +1 @@
+-- No seed data for moderation strikes
\ No newline at end of file
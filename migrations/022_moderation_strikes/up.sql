@@ -0,0 +1,15 @@
+-- Strikes against players whose messages were blocked by automatic moderation
+
+CREATE TABLE IF NOT EXISTS moderation_strikes
+(
+    id         VARCHAR(10) PRIMARY KEY,
+    player_id  VARCHAR(10) REFERENCES players (id) ON DELETE CASCADE NOT NULL,
+    rule_id    VARCHAR(64)                                          NOT NULL,
+    stage      VARCHAR(32)                                          NOT NULL,
+    match      TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_strikes_player ON moderation_strikes (player_id, created_at);
+
+COMMENT ON TABLE moderation_strikes IS 'One row per message blocked by automatic moderation, counted towards escalating mutes until they decay';
@@ -0,0 +1,7 @@
+-- Remove shadow mutes
+ALTER TABLE whispers DROP COLUMN IF EXISTS shadowed;
+ALTER TABLE messages DROP COLUMN IF EXISTS shadowed;
+DELETE FROM sanctions WHERE type = 'shadow_mute';
+ALTER TABLE sanctions DROP CONSTRAINT IF EXISTS sanctions_type_check;
+ALTER TABLE sanctions ADD CONSTRAINT sanctions_type_check CHECK (type IN ('mute', 'kick', 'ban'));
+ALTER TABLE sanctions ALTER COLUMN type TYPE VARCHAR(10);
@@ -0,0 +1,19 @@
+-- Shadow mutes, whose messages are only shown to the muted player and moderators
+
+ALTER TABLE sanctions
+    ALTER COLUMN type TYPE VARCHAR(16);
+
+ALTER TABLE sanctions
+    DROP CONSTRAINT IF EXISTS sanctions_type_check;
+
+ALTER TABLE sanctions
+    ADD CONSTRAINT sanctions_type_check CHECK (type IN ('mute', 'shadow_mute', 'kick', 'ban'));
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS shadowed BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE whispers
+    ADD COLUMN IF NOT EXISTS shadowed BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN messages.shadowed IS 'Sent while shadow muted, only shown to its author';
+COMMENT ON COLUMN whispers.shadowed IS 'Sent while shadow muted, never delivered to the recipient';
@@ -0,0 +1,3 @@
+-- Remove typed system messages
+ALTER TABLE messages DROP COLUMN IF EXISTS data;
+ALTER TABLE messages DROP COLUMN IF EXISTS kind;
@@ -0,0 +1 @@
+-- No seed data for system message kinds
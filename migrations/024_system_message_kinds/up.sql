@@ -0,0 +1,15 @@
+-- Typed system messages with structured data
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS kind VARCHAR(32);
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS data JSONB;
+
+-- Earlier system messages can only be told apart by their text, their data is not recoverable
+UPDATE messages SET kind = 'tile_confirmed' WHERE system AND kind IS NULL AND contents LIKE '**TILE CONFIRMED**%';
+UPDATE messages SET kind = 'winner' WHERE system AND kind IS NULL AND contents LIKE '**BINGO WINNER!**%';
+UPDATE messages SET kind = 'announcement' WHERE system AND kind IS NULL;
+
+COMMENT ON COLUMN messages.kind IS 'What a system message announces: tile_confirmed, winner, timer_expired or announcement';
+COMMENT ON COLUMN messages.data IS 'Structured details of a system message, such as the tile or player it is about';
@@ -0,0 +1,3 @@
+-- Remove shadowed reactions
+DELETE FROM message_reactions WHERE shadowed;
+ALTER TABLE message_reactions DROP COLUMN IF EXISTS shadowed;
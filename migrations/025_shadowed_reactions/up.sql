@@ -0,0 +1,6 @@
+-- Reactions of shadow muted players, which are only counted for the player themselves
+
+ALTER TABLE message_reactions
+    ADD COLUMN IF NOT EXISTS shadowed BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN message_reactions.shadowed IS 'Added while shadow muted, only counted for the player who reacted';
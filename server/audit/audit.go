@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Actions recorded in the audit log, named <target>.<verb>
+const (
+	PermissionsUpdate  = "permissions.update"
+	TileCreate         = "tile.create"
+	TileUpdate         = "tile.update"
+	TileDelete         = "tile.delete"
+	TileConfirm        = "tile.confirm"
+	TileRevoke         = "tile.revoke"
+	SuggestionReview   = "suggestion.review"
+	MessageDelete      = "message.delete"
+	MessagePurge       = "message.purge"
+	ChatSettingsUpdate = "chat_settings.update"
+	SanctionCreate     = "sanction.create"
+	SanctionRevoke     = "sanction.revoke"
+	RuleCreate         = "moderation_rule.create"
+	RuleUpdate         = "moderation_rule.update"
+	RuleDelete         = "moderation_rule.delete"
+	ThresholdsUpdate   = "moderation_thresholds.update"
+	ReportResolve      = "report.resolve"
+	TimerCreate        = "timer.create"
+	TimerUpdate        = "timer.update"
+	TimerDelete        = "timer.delete"
+	TimerStart         = "timer.start"
+	TimerStop          = "timer.stop"
+	TimerReset         = "timer.reset"
+	TimerPause         = "timer.pause"
+	TimerResume        = "timer.resume"
+	TimerExtend        = "timer.extend"
+)
+
+// SystemActor is recorded as the actor of actions taken automatically
+const SystemActor = "SYSTEM"
+
+// Record appends a privileged action taken during a request to the audit log. The actor is
+// the authenticated player and before and after, either of which may be nil, are stored as
+// JSON. Failures are logged rather than failing the request, as the action already happened.
+func Record(c *fiber.Ctx, action string, targetType string, targetID string, before any, after any) {
+	entry := newEntry(action, targetType, targetID, before, after)
+
+	if player, err := middleware.GetPlayerFromContext(c); err == nil && player != nil {
+		entry.ActorID = &player.ID
+	}
+
+	method := c.Method()
+	path := c.Path()
+	ip := c.IP()
+	entry.Method = &method
+	entry.Path = &path
+	entry.IP = &ip
+	if userAgent := c.Get(fiber.HeaderUserAgent); userAgent != "" {
+		entry.UserAgent = &userAgent
+	}
+
+	write(entry)
+}
+
+// RecordSystem appends an action taken automatically, outside of any request, to the audit log
+func RecordSystem(action string, targetType string, targetID string, before any, after any) {
+	entry := newEntry(action, targetType, targetID, before, after)
+	actor := SystemActor
+	entry.ActorID = &actor
+
+	write(entry)
+}
+
+func newEntry(action string, targetType string, targetID string, before any, after any) *models.AuditEntry {
+	entry := &models.AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		Before:     marshal(before),
+		After:      marshal(after),
+	}
+	if targetID != "" {
+		entry.TargetID = &targetID
+	}
+	return entry
+}
+
+// marshal encodes a state snapshot, leaving it empty when there is none
+func marshal(state any) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Printf("[Audit] Failed to encode state: %v", err)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+func write(entry *models.AuditEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := db.PersistAuditEntry(ctx, entry); err != nil {
+		log.Printf("[Audit] Failed to record %s on %s: %v", entry.Action, entry.TargetType, err)
+	}
+}
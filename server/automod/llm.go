@@ -0,0 +1,121 @@
+package automod
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/sse"
+	"wanshow-bingo/utils"
+)
+
+// llmRemovalReason is recorded on messages removed after the LLM flagged them
+const llmRemovalReason = "Removed by automatic moderation"
+
+// llmReview is a published message waiting for an LLM verdict
+type llmReview struct {
+	MessageID string
+	Contents  string
+}
+
+var (
+	llmWorkers     = utils.GetEnvInt("LLM_MODERATION_WORKERS", 4)
+	llmQueue       = make(chan llmReview, utils.GetEnvInt("LLM_MODERATION_QUEUE_SIZE", 256))
+	llmWorkersOnce sync.Once
+)
+
+// ReviewMessage queues a published message for LLM moderation. Messages found to be toxic
+// are deleted afterwards. It does nothing when no LLM endpoint is configured, or when the
+// LLM check already runs synchronously as a pipeline stage.
+func ReviewMessage(message *models.Message) {
+	if !utils.LLMModerationEnabled() || utils.DefaultModerationPipeline().HasStage("llm") {
+		return
+	}
+	llmWorkersOnce.Do(startLLMWorkers)
+
+	select {
+	case llmQueue <- llmReview{MessageID: message.ID, Contents: message.Contents}:
+	default:
+		log.Printf("[Automod] LLM review queue full, skipping message %s", message.ID)
+	}
+}
+
+// CheckBeforeDelivery runs the LLM check on content that cannot be taken back once it is
+// delivered, such as whispers, before it is sent. Everything is allowed when no LLM endpoint is
+// configured, or when the LLM check already runs as a pipeline stage.
+func CheckBeforeDelivery(contents string) *utils.ModerationResult {
+	if !utils.LLMModerationEnabled() || utils.DefaultModerationPipeline().HasStage("llm") {
+		return &utils.ModerationResult{Allowed: true}
+	}
+
+	result := utils.CheckLLMModeration(contents)
+	if !result.Allowed {
+		result.Stage = "llm"
+		recordHit(result)
+	}
+	return result
+}
+
+// startLLMWorkers starts the worker pool the first time a message is queued
+func startLLMWorkers() {
+	for range max(llmWorkers, 1) {
+		go runLLMWorker()
+	}
+}
+
+func runLLMWorker() {
+	for review := range llmQueue {
+		reviewMessage(review)
+	}
+}
+
+// reviewMessage asks the LLM for a verdict and removes the message if it is flagged
+func reviewMessage(review llmReview) {
+	resp, err := utils.QueryLLMModeration(review.Contents)
+	if err != nil {
+		// Fail open, the message has already passed the local checks
+		log.Printf("[Automod] LLM review of message %s failed: %v", review.MessageID, err)
+		return
+	}
+
+	result := utils.LLMVerdict(resp)
+	if result.Allowed {
+		return
+	}
+	result.Stage = "llm"
+	recordHit(result)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The message may have been edited since, in which case the new contents are reviewed
+	// separately
+	message, err := db.GetMessageByID(ctx, review.MessageID)
+	if err != nil || message.DeletedAt != nil || message.Contents != review.Contents {
+		return
+	}
+
+	reason := llmRemovalReason
+	if err := db.DeleteMessage(ctx, message.ID, "SYSTEM", &reason); err != nil {
+		log.Printf("[Automod] Failed to remove flagged message %s: %v", message.ID, err)
+		return
+	}
+	log.Printf("[Automod] Removed message %s of player %s: %s", message.ID, message.PlayerID, result.Reason)
+	audit.RecordSystem(audit.MessageDelete, "message", message.ID, message, map[string]interface{}{
+		"reason":  reason,
+		"rule_id": result.RuleID,
+	})
+
+	chatHub := sse.GetChatHub()
+	if chatHub != nil {
+		chatHub.BroadcastEvent("chat.message.deleted", map[string]interface{}{
+			"id":         message.ID,
+			"show_id":    message.ShowID,
+			"deleted_by": "SYSTEM",
+			"reason":     &reason,
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package automod
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/utils"
+)
+
+// refreshInterval is how often rules are reloaded and hit counts written, to pick up rule
+// changes made through other instances
+var refreshInterval = time.Duration(utils.GetEnvInt("MODERATION_RULES_REFRESH_SECONDS", 60)) * time.Second
+
+// defaultTermReason is shown for blocked terms that were added without a reason
+const defaultTermReason = "Message contains a blocked term"
+
+// hits are counted in memory and written to the database on every refresh
+var (
+	hits   = make(map[[2]string]*models.ModerationRuleHit)
+	hitsMu sync.Mutex
+)
+
+func init() {
+	utils.OnModerationHit(recordHit)
+}
+
+// Init loads the moderation rules from the database and starts refreshing them.
+// It must be called after the database pool has been initialised.
+func Init() {
+	if err := Reload(); err != nil {
+		log.Printf("Failed to load moderation rules, using the built-in rules: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := Reload(); err != nil {
+				log.Printf("Failed to reload moderation rules: %v", err)
+			}
+			FlushHits()
+		}
+	}()
+
+	log.Println("Moderation rules initialized")
+}
+
+// Reload replaces the rules of the moderation pipeline with the rules stored in the database
+func Reload() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rules, err := db.GetModerationRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	thresholds, err := db.GetModerationThresholds(ctx)
+	if err != nil {
+		return err
+	}
+
+	utils.SetModerationRules(Compile(rules, thresholds))
+	return nil
+}
+
+// Compile turns stored rules into the rules used by the moderation pipeline, leaving out
+// disabled rules and regular expressions that do not compile
+func Compile(rules []models.ModerationRule, thresholds *models.ModerationThresholds) *utils.ModerationRules {
+	compiled := &utils.ModerationRules{
+		CapsRatio:     thresholds.CapsRatio,
+		CapsMinLength: thresholds.CapsMinLength,
+		RepeatLimit:   thresholds.RepeatLimit,
+	}
+
+	// Terms are grouped by rule ID, keeping the order the groups first appear in
+	groups := make(map[string]int)
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+
+		reason := defaultTermReason
+		if rule.Reason != nil && *rule.Reason != "" {
+			reason = *rule.Reason
+		}
+
+		switch rule.Kind {
+		case models.ModerationRuleTerm:
+			i, ok := groups[rule.RuleID]
+			if !ok {
+				i = len(compiled.Keywords)
+				groups[rule.RuleID] = i
+				compiled.Keywords = append(compiled.Keywords, utils.KeywordRule{ID: rule.RuleID, Reason: reason})
+			}
+			compiled.Keywords[i].Words = append(compiled.Keywords[i].Words, utils.NormalizeTerm(rule.Pattern))
+		case models.ModerationRuleRegex:
+			pattern, err := CompileRegex(rule.Pattern)
+			if err != nil {
+				log.Printf("Skipping moderation rule %s, invalid regex: %v", rule.ID, err)
+				continue
+			}
+			compiled.Regexes = append(compiled.Regexes, utils.RegexRule{ID: rule.RuleID, Reason: reason, Pattern: pattern})
+		case models.ModerationRuleAllow:
+			compiled.Allowed = append(compiled.Allowed, utils.NormalizeContent(rule.Pattern))
+		}
+	}
+
+	return compiled
+}
+
+// CompileRegex compiles the pattern of a regex rule, which always matches case-insensitively
+func CompileRegex(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// recordHit counts a rejected message against the rule that matched it
+func recordHit(result *utils.ModerationResult) {
+	key := [2]string{result.RuleID, result.Match}
+	now := time.Now()
+
+	hitsMu.Lock()
+	defer hitsMu.Unlock()
+
+	hit, ok := hits[key]
+	if !ok {
+		hit = &models.ModerationRuleHit{RuleID: result.RuleID, Match: result.Match}
+		hits[key] = hit
+	}
+	hit.Hits++
+	hit.LastHitAt = &now
+}
+
+// FlushHits writes the hits counted since the last flush to the database
+func FlushHits() {
+	hitsMu.Lock()
+	pending := make([]models.ModerationRuleHit, 0, len(hits))
+	for _, hit := range hits {
+		pending = append(pending, *hit)
+	}
+	hits = make(map[[2]string]*models.ModerationRuleHit)
+	hitsMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.RecordModerationRuleHits(ctx, pending); err != nil {
+		log.Printf("Failed to record moderation rule hits: %v", err)
+	}
+}
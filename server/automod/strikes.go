@@ -0,0 +1,139 @@
+package automod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/utils"
+)
+
+// EscalationAction is what happens to a player once their strikes reach a step
+type EscalationAction string
+
+const (
+	// EscalateMute mutes the player in chat for the step's duration
+	EscalateMute EscalationAction = "mute"
+	// EscalateReview flags the player for a moderator to review
+	EscalateReview EscalationAction = "review"
+)
+
+// EscalationStep is applied when a player's active strikes reach Strikes
+type EscalationStep struct {
+	Strikes  int
+	Action   EscalationAction
+	Duration time.Duration
+}
+
+// StrikeDecay is how long a strike counts towards escalation
+var StrikeDecay = time.Duration(utils.GetEnvInt("AUTOMOD_STRIKE_DECAY_MINUTES", 24*60)) * time.Minute
+
+// strikeExemptStages are moderation stages whose rejections are not held against the player,
+// formatting mistakes by default
+var strikeExemptStages = func() []string {
+	stages, ok := os.LookupEnv("AUTOMOD_STRIKE_EXEMPT_STAGES")
+	if !ok {
+		stages = "markdown"
+	}
+	return strings.Split(stages, ",")
+}()
+
+// escalationSteps escalate from a short mute to a longer one and then to a moderator. A step
+// with zero strikes is turned off.
+var escalationSteps = []EscalationStep{
+	{
+		Strikes:  utils.GetEnvInt("AUTOMOD_MUTE_STRIKES", 3),
+		Action:   EscalateMute,
+		Duration: time.Duration(utils.GetEnvInt("AUTOMOD_MUTE_MINUTES", 10)) * time.Minute,
+	},
+	{
+		Strikes:  utils.GetEnvInt("AUTOMOD_LONG_MUTE_STRIKES", 5),
+		Action:   EscalateMute,
+		Duration: time.Duration(utils.GetEnvInt("AUTOMOD_LONG_MUTE_MINUTES", 60)) * time.Minute,
+	},
+	{
+		Strikes: utils.GetEnvInt("AUTOMOD_REVIEW_STRIKES", 7),
+		Action:  EscalateReview,
+	},
+}
+
+// StrikeOutcome is the state of a player after a strike was recorded against them
+type StrikeOutcome struct {
+	// Active are the strikes that have not decayed yet, including the new one
+	Active []models.Strike
+	// Step is the escalation reached with this strike, if any
+	Step *EscalationStep
+}
+
+// RecordStrike records a strike for a message moderation blocked and works out whether it
+// escalates. Strikes older than StrikeDecay are not counted. It returns nil without recording
+// anything when the stage that blocked the message is exempt.
+func RecordStrike(ctx context.Context, playerID string, result *utils.ModerationResult) (*StrikeOutcome, error) {
+	for _, stage := range strikeExemptStages {
+		if strings.TrimSpace(stage) == result.Stage {
+			return nil, nil
+		}
+	}
+
+	strike := &models.Strike{
+		PlayerID: playerID,
+		RuleID:   result.RuleID,
+		Stage:    result.Stage,
+	}
+	if result.Match != "" {
+		strike.Match = &result.Match
+	}
+	if err := db.PersistStrike(ctx, strike); err != nil {
+		return nil, err
+	}
+
+	active, err := db.GetStrikesSince(ctx, playerID, time.Now().Add(-StrikeDecay))
+	if err != nil {
+		return nil, err
+	}
+
+	return &StrikeOutcome{
+		Active: active,
+		Step:   escalationFor(escalationSteps, len(active)),
+	}, nil
+}
+
+// escalationFor returns the step reached with exactly this many strikes, so each step is
+// applied once as strikes add up
+func escalationFor(steps []EscalationStep, strikes int) *EscalationStep {
+	for i := range steps {
+		if steps[i].Strikes > 0 && steps[i].Strikes == strikes {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+// Summary describes the active strikes by rule, most frequent first
+func (o *StrikeOutcome) Summary() string {
+	counts := make(map[string]int)
+	for _, strike := range o.Active {
+		counts[strike.RuleID]++
+	}
+
+	rules := make([]string, 0, len(counts))
+	for rule := range counts {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if counts[rules[i]] != counts[rules[j]] {
+			return counts[rules[i]] > counts[rules[j]]
+		}
+		return rules[i] < rules[j]
+	})
+
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = fmt.Sprintf("%s x%d", rule, counts[rule])
+	}
+	return fmt.Sprintf("%d automatic moderation strikes: %s", len(o.Active), strings.Join(parts, ", "))
+}
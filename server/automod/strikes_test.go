@@ -0,0 +1,42 @@
+package automod
+
+import (
+	"testing"
+	"time"
+	"wanshow-bingo/db/models"
+)
+
+func TestEscalationFor(t *testing.T) {
+	steps := []EscalationStep{
+		{Strikes: 3, Action: EscalateMute, Duration: 10 * time.Minute},
+		{Strikes: 5, Action: EscalateMute, Duration: time.Hour},
+		{Strikes: 0, Action: EscalateReview},
+	}
+
+	cases := map[int]*EscalationStep{
+		1: nil,
+		3: &steps[0],
+		4: nil,
+		5: &steps[1],
+		6: nil,
+		0: nil,
+	}
+	for strikes, expected := range cases {
+		if got := escalationFor(steps, strikes); got != expected {
+			t.Errorf("escalationFor(%d) = %+v, expected %+v", strikes, got, expected)
+		}
+	}
+}
+
+func TestStrikeSummary(t *testing.T) {
+	outcome := &StrikeOutcome{Active: []models.Strike{
+		{RuleID: "spam.caps"},
+		{RuleID: "keyword.profanity"},
+		{RuleID: "keyword.profanity"},
+	}}
+
+	expected := "3 automatic moderation strikes: keyword.profanity x2, spam.caps x1"
+	if got := outcome.Summary(); got != expected {
+		t.Errorf("Summary() = %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/matoous/go-nanoid/v2"
+)
+
+// PersistAuditEntry appends an entry to the audit log
+func PersistAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	if entry.ID == "" {
+		entry.ID, _ = gonanoid.New(10)
+	}
+
+	return pool.QueryRow(ctx, `
+		INSERT INTO audit_log (id, actor_id, action, target_type, target_id, before, after, method, path, ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at
+	`, entry.ID, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, entry.Before, entry.After,
+		entry.Method, entry.Path, entry.IP, entry.UserAgent).Scan(&entry.CreatedAt)
+}
+
+// GetAuditEntries retrieves the audit log entries matching a filter, newest first. hasMore
+// reports whether older entries match as well.
+func GetAuditEntries(ctx context.Context, filter models.AuditFilter) (entries []models.AuditEntry, hasMore bool, err error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, false, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, actor_id, action, target_type, target_id, before, after, method, path, ip, user_agent, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR actor_id = $1)
+		  AND ($2 = '' OR action = $2 OR action LIKE $2 || '.%')
+		  AND ($3 = '' OR target_type = $3)
+		  AND ($4 = '' OR target_id = $4)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
+		  AND ($7 = '' OR (created_at, id) < (SELECT created_at, id FROM audit_log WHERE id = $7))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $8
+	`, filter.ActorID, filter.Action, filter.TargetType, filter.TargetID, filter.Since, filter.Until, filter.Before, filter.Limit+1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries, err = pgx.CollectRows(rows, pgx.RowToStructByName[models.AuditEntry])
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(entries) > filter.Limit {
+		hasMore = true
+		entries = entries[:filter.Limit]
+	}
+
+	return entries, hasMore, nil
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetChatSettings retrieves the chat settings of a show, with everything turned off if none were set
+func GetChatSettings(ctx context.Context, showID string) (*models.ChatSettings, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	settings := &models.ChatSettings{ShowID: showID}
+	err := pool.QueryRow(ctx, `
+		SELECT slow_mode_seconds, min_account_age_minutes, updated_by, updated_at
+		FROM show_chat_settings
+		WHERE show_id = $1
+	`, showID).Scan(&settings.SlowModeSeconds, &settings.MinAccountAgeMinutes, &settings.UpdatedBy, &settings.UpdatedAt)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// PersistChatSettings saves the chat settings of a show
+func PersistChatSettings(ctx context.Context, settings *models.ChatSettings) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	return pool.QueryRow(ctx, `
+		INSERT INTO show_chat_settings (show_id, slow_mode_seconds, min_account_age_minutes, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (show_id) DO UPDATE
+		SET slow_mode_seconds = EXCLUDED.slow_mode_seconds,
+		    min_account_age_minutes = EXCLUDED.min_account_age_minutes,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`, settings.ShowID, settings.SlowModeSeconds, settings.MinAccountAgeMinutes, settings.UpdatedBy).Scan(&settings.UpdatedAt)
+}
+
+// GetLastMessageTime returns when a player last posted during a show, or nil if they have not
+func GetLastMessageTime(ctx context.Context, showID string, playerID string) (*time.Time, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	var last *time.Time
+	err := pool.QueryRow(ctx, `
+		SELECT MAX(created_at) FROM messages WHERE show_id = $1 AND player_id = $2
+	`, showID, playerID).Scan(&last)
+	return last, err
+}
+
+// GetRecentDuplicateTime returns when a player last posted the same contents during a show
+// since the given time, or nil if they have not
+func GetRecentDuplicateTime(ctx context.Context, showID string, playerID string, contents string, since time.Time) (*time.Time, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	var last *time.Time
+	err := pool.QueryRow(ctx, `
+		SELECT MAX(created_at) FROM messages
+		WHERE show_id = $1 AND player_id = $2 AND created_at > $3 AND LOWER(TRIM(contents)) = $4
+	`, showID, playerID, since, strings.ToLower(strings.TrimSpace(contents))).Scan(&last)
+	return last, err
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -84,7 +83,8 @@ func RunMigrations(ctx context.Context) error {
 
 	var versions []string
 	for _, entry := range entries {
-		if entry.IsDir() && strings.HasPrefix(entry.Name(), "00") {
+		// Migration directories are zero-padded and numbered, e.g. 001_core or 010_timer_visibility
+		if entry.IsDir() && len(entry.Name()) > 0 && entry.Name()[0] >= '0' && entry.Name()[0] <= '9' {
 			versions = append(versions, entry.Name())
 		}
 	}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"errors"
+	"slices"
 	"wanshow-bingo/db/models"
 
 	"github.com/jackc/pgx/v5"
@@ -17,9 +18,9 @@ func PersistMessage(ctx context.Context, message *models.Message, tx ...pgx.Tx)
 			// New message, generate ID and insert
 			message.ID, _ = gonanoid.New(10)
 			_, err := tx[0].Exec(ctx, `
-				INSERT INTO messages (id, show_id, player_id, contents, system, replying)
-				VALUES ($1, $2, $3, $4, $5, $6)
-			`, message.ID, message.ShowID, message.PlayerID, message.Contents, message.System, message.Replying)
+				INSERT INTO messages (id, show_id, player_id, contents, system, replying, mentions, shadowed, kind, data)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			`, message.ID, message.ShowID, message.PlayerID, message.Contents, message.System, message.Replying, message.MentionIDs(), message.Shadowed, message.Kind, message.Data)
 			return err
 		} else {
 			// Existing message, update
@@ -41,9 +42,9 @@ func PersistMessage(ctx context.Context, message *models.Message, tx ...pgx.Tx)
 			// New message, generate ID and insert
 			message.ID, _ = gonanoid.New(10)
 			_, err := pool.Exec(ctx, `
-				INSERT INTO messages (id, show_id, player_id, contents, system, replying)
-				VALUES ($1, $2, $3, $4, $5, $6)
-			`, message.ID, message.ShowID, message.PlayerID, message.Contents, message.System, message.Replying)
+				INSERT INTO messages (id, show_id, player_id, contents, system, replying, mentions, shadowed, kind, data)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			`, message.ID, message.ShowID, message.PlayerID, message.Contents, message.System, message.Replying, message.MentionIDs(), message.Shadowed, message.Kind, message.Data)
 			return err
 		} else {
 			// Existing message, update
@@ -63,7 +64,7 @@ func GetMessageByID(ctx context.Context, id string, tx ...pgx.Tx) (*models.Messa
 
 	if len(tx) > 0 {
 		row = tx[0].QueryRow(ctx, `
-			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at
+			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at, deleted_by, delete_reason, edited_at, mentions, hidden_at, shadowed, kind, data
 			FROM messages
 			WHERE id = $1 AND deleted_at IS NULL
 		`, id)
@@ -73,7 +74,7 @@ func GetMessageByID(ctx context.Context, id string, tx ...pgx.Tx) (*models.Messa
 			return nil, errors.New("database not available")
 		}
 		row = pool.QueryRow(ctx, `
-			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at
+			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at, deleted_by, delete_reason, edited_at, mentions, hidden_at, shadowed, kind, data
 			FROM messages
 			WHERE id = $1 AND deleted_at IS NULL
 		`, id)
@@ -82,7 +83,8 @@ func GetMessageByID(ctx context.Context, id string, tx ...pgx.Tx) (*models.Messa
 	var message models.Message
 	err := row.Scan(
 		&message.ID, &message.ShowID, &message.PlayerID, &message.Contents, &message.System, &message.Replying,
-		&message.CreatedAt, &message.UpdatedAt, &message.DeletedAt,
+		&message.CreatedAt, &message.UpdatedAt, &message.DeletedAt, &message.DeletedBy, &message.DeleteReason, &message.EditedAt, &message.Mentions, &message.HiddenAt, &message.Shadowed,
+		&message.Kind, &message.Data,
 	)
 
 	if err != nil {
@@ -116,8 +118,9 @@ func SaveMessage(ctx context.Context, msg *models.Message, tx ...pgx.Tx) error {
 	return PersistMessage(ctx, msg, tx...)
 }
 
-// GetMessageHistory retrieves recent messages for the latest show
-func GetMessageHistory(ctx context.Context, tx ...pgx.Tx) ([]models.Message, error) {
+// GetMessageHistory retrieves recent messages for the latest show, including the shadowed
+// messages of viewerID
+func GetMessageHistory(ctx context.Context, viewerID string, tx ...pgx.Tx) ([]models.Message, error) {
 	latestShow, err := GetLatestShow(ctx, tx...)
 	if err != nil {
 		return nil, err
@@ -126,24 +129,24 @@ func GetMessageHistory(ctx context.Context, tx ...pgx.Tx) ([]models.Message, err
 	var rows pgx.Rows
 	if len(tx) > 0 {
 		rows, err = tx[0].Query(ctx, `
-			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at
+			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at, deleted_by, delete_reason, edited_at, mentions, hidden_at, shadowed, kind, data
 			FROM messages
-			WHERE show_id = $1 AND deleted_at IS NULL
+			WHERE show_id = $1 AND deleted_at IS NULL AND hidden_at IS NULL AND (NOT shadowed OR player_id = $2)
 			ORDER BY created_at DESC
 			LIMIT 30
-		`, latestShow.ID)
+		`, latestShow.ID, viewerID)
 	} else {
 		pool := Pool()
 		if pool == nil {
 			return nil, errors.New("database not available")
 		}
 		rows, err = pool.Query(ctx, `
-			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at
+			SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at, deleted_by, delete_reason, edited_at, mentions, hidden_at, shadowed, kind, data
 			FROM messages
-			WHERE show_id = $1 AND deleted_at IS NULL
+			WHERE show_id = $1 AND deleted_at IS NULL AND hidden_at IS NULL AND (NOT shadowed OR player_id = $2)
 			ORDER BY created_at DESC
 			LIMIT 30
-		`, latestShow.ID)
+		`, latestShow.ID, viewerID)
 	}
 
 	if err != nil {
@@ -156,7 +159,8 @@ func GetMessageHistory(ctx context.Context, tx ...pgx.Tx) ([]models.Message, err
 		var message models.Message
 		err := rows.Scan(
 			&message.ID, &message.ShowID, &message.PlayerID, &message.Contents, &message.System, &message.Replying,
-			&message.CreatedAt, &message.UpdatedAt, &message.DeletedAt,
+			&message.CreatedAt, &message.UpdatedAt, &message.DeletedAt, &message.DeletedBy, &message.DeleteReason, &message.EditedAt, &message.Mentions, &message.HiddenAt, &message.Shadowed,
+			&message.Kind, &message.Data,
 		)
 		if err != nil {
 			return nil, err
@@ -166,3 +170,274 @@ func GetMessageHistory(ctx context.Context, tx ...pgx.Tx) ([]models.Message, err
 
 	return messages, rows.Err()
 }
+
+// GetMessageThread retrieves the whole conversation a message belongs to: the
+// message at the root of its reply chain and every reply below it, oldest first. Shadowed
+// messages are only included when viewerID sent them.
+func GetMessageThread(ctx context.Context, messageID string, viewerID string) ([]models.Message, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, replying FROM messages WHERE id = $1
+			UNION ALL
+			SELECT m.id, m.replying FROM messages m JOIN ancestors a ON m.id = a.replying
+		), thread AS (
+			SELECT m.id FROM messages m JOIN ancestors a ON m.id = a.id WHERE a.replying IS NULL
+			UNION ALL
+			SELECT m.id FROM messages m JOIN thread t ON m.replying = t.id
+		)
+		SELECT id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at, deleted_by, delete_reason, edited_at, mentions, hidden_at, shadowed, kind, data
+		FROM messages
+		WHERE id IN (SELECT id FROM thread) AND deleted_at IS NULL AND hidden_at IS NULL
+		  AND (NOT shadowed OR player_id = $2)
+		ORDER BY created_at
+	`, messageID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Message])
+}
+
+// DeleteMessage soft deletes a message, recording who deleted it and why
+func DeleteMessage(ctx context.Context, messageID string, deletedBy string, reason *string) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	result, err := pool.Exec(ctx, `
+		UPDATE messages
+		SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $2, delete_reason = $3
+		WHERE id = $1 AND deleted_at IS NULL
+	`, messageID, deletedBy, reason)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("message not found")
+	}
+
+	return nil
+}
+
+// SetMessageHidden hides a message from chat until it has been reviewed, or shows it again,
+// returning false if it was already in that state
+func SetMessageHidden(ctx context.Context, messageID string, hidden bool) (bool, error) {
+	pool := Pool()
+	if pool == nil {
+		return false, errors.New("database not available")
+	}
+
+	result, err := pool.Exec(ctx, `
+		UPDATE messages
+		SET hidden_at = CASE WHEN $2 THEN CURRENT_TIMESTAMP END
+		WHERE id = $1 AND deleted_at IS NULL AND (hidden_at IS NULL) = $2
+	`, messageID, hidden)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// PurgePlayerMessages soft deletes every message a player sent during a show
+// and returns the IDs of the deleted messages
+func PurgePlayerMessages(ctx context.Context, showID string, playerID string, deletedBy string, reason *string) ([]string, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		UPDATE messages
+		SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $3, delete_reason = $4
+		WHERE show_id = $1 AND player_id = $2 AND deleted_at IS NULL
+		RETURNING id
+	`, showID, playerID, deletedBy, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// EditMessage replaces the contents of a message, keeping the previous contents as a revision.
+// Edits made while shadow muted shadow the message.
+func EditMessage(ctx context.Context, messageID string, contents string, editedBy string, shadowed bool) (*models.Message, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var previous string
+	err = tx.QueryRow(ctx, `
+		SELECT contents FROM messages WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, messageID).Scan(&previous)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("message not found")
+		}
+		return nil, err
+	}
+
+	revisionID, _ := gonanoid.New(10)
+	_, err = tx.Exec(ctx, `
+		INSERT INTO message_revisions (id, message_id, contents, edited_by)
+		VALUES ($1, $2, $3, $4)
+	`, revisionID, messageID, previous, editedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE messages
+		SET contents = $2, edited_at = CURRENT_TIMESTAMP, shadowed = shadowed OR $3
+		WHERE id = $1
+	`, messageID, contents, shadowed)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := GetMessageByID(ctx, messageID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// GetMessageRevisions retrieves the previous contents of a message, oldest first
+func GetMessageRevisions(ctx context.Context, messageID string) ([]models.MessageRevision, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, message_id, contents, edited_by, created_at
+		FROM message_revisions
+		WHERE message_id = $1
+		ORDER BY created_at
+	`, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.MessageRevision])
+}
+
+// GetMessagesPage retrieves up to limit messages of a show, oldest first. With before, it returns
+// the messages just older than that cursor, with after the ones just newer, and with neither the
+// latest messages. hasMore reports whether further messages exist in the direction paged.
+// Shadowed messages are only included when viewerID sent them.
+func GetMessagesPage(ctx context.Context, showID string, viewerID string, before *models.MessageCursor, after *models.MessageCursor, limit int) (messages []models.Message, hasMore bool, err error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, false, errors.New("database not available")
+	}
+
+	const columns = `id, show_id, player_id, contents, system, replying, created_at, updated_at, deleted_at, deleted_by, delete_reason, edited_at, mentions, hidden_at, shadowed, kind, data`
+
+	var rows pgx.Rows
+	switch {
+	case after != nil:
+		rows, err = pool.Query(ctx, `
+			SELECT `+columns+`
+			FROM messages
+			WHERE show_id = $1 AND deleted_at IS NULL AND hidden_at IS NULL AND (NOT shadowed OR player_id = $2)
+			  AND (created_at, id) > ($3, $4)
+			ORDER BY created_at, id
+			LIMIT $5
+		`, showID, viewerID, after.CreatedAt, after.ID, limit+1)
+	case before != nil:
+		rows, err = pool.Query(ctx, `
+			SELECT `+columns+`
+			FROM messages
+			WHERE show_id = $1 AND deleted_at IS NULL AND hidden_at IS NULL AND (NOT shadowed OR player_id = $2)
+			  AND (created_at, id) < ($3, $4)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $5
+		`, showID, viewerID, before.CreatedAt, before.ID, limit+1)
+	default:
+		rows, err = pool.Query(ctx, `
+			SELECT `+columns+`
+			FROM messages
+			WHERE show_id = $1 AND deleted_at IS NULL AND hidden_at IS NULL AND (NOT shadowed OR player_id = $2)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $3
+		`, showID, viewerID, limit+1)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	messages, err = pgx.CollectRows(rows, pgx.RowToStructByName[models.Message])
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(messages) > limit {
+		hasMore = true
+		messages = messages[:limit]
+	}
+
+	// Pages going backwards were read newest first
+	if after == nil {
+		slices.Reverse(messages)
+	}
+
+	return messages, hasMore, nil
+}
+
+// StreamShowTranscript calls fn with every message of a show, oldest first, joined to its author
+// and timed against the show's start. Deleted messages are left out when omitDeleted is set.
+// Rows are handed over as they are read, so large shows are never held in memory.
+func StreamShowTranscript(ctx context.Context, showID string, omitDeleted bool, fn func(entry *models.TranscriptEntry) error) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT m.id, m.player_id, p.display_name, m.contents, m.system, m.kind, m.replying, m.created_at, m.edited_at,
+		       m.deleted_at, m.deleted_by, m.delete_reason, m.hidden_at IS NOT NULL AS hidden, m.shadowed,
+		       EXTRACT(EPOCH FROM m.created_at - s.actual_start_time)::BIGINT AS offset_seconds
+		FROM messages m
+		JOIN shows s ON s.id = m.show_id
+		LEFT JOIN players p ON p.id = m.player_id
+		WHERE m.show_id = $1 AND (NOT $2 OR m.deleted_at IS NULL)
+		ORDER BY m.created_at, m.id
+	`, showID, omitDeleted)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := pgx.RowToStructByName[models.TranscriptEntry](rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -2,9 +2,14 @@ package models
 
 import (
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -69,3 +74,239 @@ func sortedMap(m map[string]interface{}) map[string]interface{} {
 	}
 	return sorted
 }
+
+// Validate checks that an action has the fields its type requires.
+func (a *TimerAction) Validate() error {
+	switch a.Type {
+	case TimerActionConfirmTile:
+		if a.TileID == "" {
+			return errors.New("confirm_tile action requires a tile_id")
+		}
+	case TimerActionSystemMessage:
+		if a.Message == "" {
+			return errors.New("system_message action requires a message")
+		}
+	case TimerActionHostEvent:
+		if a.Event == "" {
+			return errors.New("host_event action requires an event")
+		}
+	default:
+		return fmt.Errorf("unknown timer action type %q", a.Type)
+	}
+	return nil
+}
+
+// Actions decodes and validates the expiry actions stored in the timer settings.
+// A timer without an "actions" key has no actions.
+func (t *Timer) Actions() ([]TimerAction, error) {
+	raw, ok := t.Settings["actions"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []TimerAction
+	if err := json.Unmarshal(data, &actions); err != nil {
+		return nil, fmt.Errorf("invalid timer actions: %w", err)
+	}
+
+	for i := range actions {
+		if err := actions[i].Validate(); err != nil {
+			return nil, fmt.Errorf("action %d: %w", i, err)
+		}
+	}
+
+	return actions, nil
+}
+
+// IsPublic reports whether the timer is shown to players as well as hosts
+func (t *Timer) IsPublic() bool {
+	return t.Visibility != TimerVisibilityHost
+}
+
+// VisibleTo reports whether a player may see the timer. Host-only timers are hidden from anyone
+// who cannot create timers, including anonymous viewers, for whom player is nil.
+func (t *Timer) VisibleTo(player *Player) bool {
+	if t.IsPublic() {
+		return true
+	}
+	return player != nil && player.CanCreateTimers()
+}
+
+// CanCreateTimers reports whether a player may create timers shown to the whole show
+func (p *Player) CanCreateTimers() bool {
+	return p.Permissions.HasPermission(PermCanHost) || p.Permissions.HasPermission(PermCanManageTimers)
+}
+
+// Valid reports whether the visibility is one of the known values
+func (v TimerVisibility) Valid() bool {
+	return v == TimerVisibilityPublic || v == TimerVisibilityHost
+}
+
+// messagePreviewLength is the number of characters kept in a reply preview
+const messagePreviewLength = 100
+
+// Preview returns a copy of the message with its contents trimmed for display above a reply
+func (m *Message) Preview() MessagePreview {
+	contents := []rune(m.Contents)
+	preview := MessagePreview{
+		ID:       m.ID,
+		PlayerID: m.PlayerID,
+		Contents: m.Contents,
+		System:   m.System,
+	}
+	if len(contents) > messagePreviewLength {
+		preview.Contents = string(contents[:messagePreviewLength]) + "…"
+	}
+	return preview
+}
+
+// AllowsWhispers reports whether the player accepts whispers, which is the default
+// unless the "allow_whispers" setting is false
+func (p *Player) AllowsWhispers() bool {
+	if p.Settings == nil {
+		return true
+	}
+	allow, ok := (*p.Settings)["allow_whispers"].(bool)
+	return !ok || allow
+}
+
+// Valid reports whether the sanction type is one of the known values
+func (t SanctionType) Valid() bool {
+	return t == SanctionMute || t == SanctionShadowMute || t == SanctionKick || t == SanctionBan
+}
+
+// Disconnects reports whether the sanction removes the player from live streams
+func (t SanctionType) Disconnects() bool {
+	return t == SanctionKick || t == SanctionBan
+}
+
+// Valid reports whether the sanction scope is one of the known values
+func (s SanctionScope) Valid() bool {
+	return s == SanctionScopeChat || s == SanctionScopeSuggestions || s == SanctionScopeGlobal
+}
+
+// Covers reports whether a sanction with this scope applies to the given scope
+func (s SanctionScope) Covers(scope SanctionScope) bool {
+	return s == SanctionScopeGlobal || s == scope
+}
+
+// MessageCursor marks a position in a show's chat, ordered by creation time then ID
+type MessageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Cursor returns the pagination cursor pointing at this message
+func (m *Message) Cursor() MessageCursor {
+	return MessageCursor{CreatedAt: m.CreatedAt, ID: m.ID}
+}
+
+// Offset formats how far into the show a transcript entry was sent as [-]HH:MM:SS, or returns
+// an empty string when the show has no start time
+func (e *TranscriptEntry) Offset() string {
+	if e.OffsetSeconds == nil {
+		return ""
+	}
+
+	seconds := *e.OffsetSeconds
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+}
+
+// Encode turns the cursor into an opaque string for use in URLs
+func (c MessageCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// DecodeMessageCursor parses a cursor produced by MessageCursor.Encode
+func DecodeMessageCursor(value string) (MessageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return MessageCursor{}, errors.New("invalid cursor")
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return MessageCursor{}, errors.New("invalid cursor")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return MessageCursor{}, errors.New("invalid cursor")
+	}
+
+	return MessageCursor{CreatedAt: t, ID: id}, nil
+}
+
+// MentionIDs returns the mentioned player IDs, never nil so it is stored as an empty array
+func (m *Message) MentionIDs() []string {
+	if m.Mentions == nil {
+		return []string{}
+	}
+	return m.Mentions
+}
+
+// VisibleTo reports whether a player may see the message. Messages hidden by reports are seen by
+// nobody, and shadowed messages only by their author. viewerID is empty for anonymous viewers.
+func (m *Message) VisibleTo(viewerID string) bool {
+	if m.DeletedAt != nil || m.HiddenAt != nil {
+		return false
+	}
+	return !m.Shadowed || (viewerID != "" && m.PlayerID == viewerID)
+}
+
+// Command returns the slash command a player's message was posted by, or empty for
+// messages the player typed themselves
+func (m *Message) Command() string {
+	if m.System {
+		return ""
+	}
+	command, _ := m.Data["command"].(string)
+	return command
+}
+
+// IsReactionEmoji reports whether emoji is one of the reactions players may use
+func IsReactionEmoji(emoji string) bool {
+	return slices.Contains(ReactionEmojis, emoji)
+}
+
+// Valid reports whether the kind is one of the known moderation rule kinds
+func (k ModerationRuleKind) Valid() bool {
+	return k == ModerationRuleTerm || k == ModerationRuleRegex || k == ModerationRuleAllow
+}
+
+// Valid reports whether the report category is one of the known values
+func (c ReportCategory) Valid() bool {
+	switch c {
+	case ReportSpam, ReportHarassment, ReportHate, ReportSexual, ReportSpoilers, ReportOther:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether the report action is one of the known values
+func (a ReportAction) Valid() bool {
+	switch a {
+	case ReportDismiss, ReportDelete, ReportMute, ReportKick, ReportBan:
+		return true
+	}
+	return false
+}
+
+// SanctionType returns the sanction the action issues, if any
+func (a ReportAction) SanctionType() (SanctionType, bool) {
+	switch a {
+	case ReportMute, ReportKick, ReportBan:
+		return SanctionType(a), true
+	}
+	return "", false
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -127,27 +128,198 @@ type Message struct {
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at" db:"deleted_at"`
+
+	// Kind and Data describe what a system message announces, so clients need not parse Contents.
+	// Player messages posted by a slash command have no Kind, and Data names the command.
+	Kind *SystemMessageKind     `json:"kind,omitempty" db:"kind"`
+	Data map[string]interface{} `json:"data,omitempty" db:"data"`
+
+	DeletedBy    *string `json:"deleted_by,omitempty" db:"deleted_by"`
+	DeleteReason *string `json:"delete_reason,omitempty" db:"delete_reason"`
+
+	EditedAt *time.Time `json:"edited_at" db:"edited_at"`
+	Mentions []string   `json:"mentions" db:"mentions"`
+	HiddenAt *time.Time `json:"hidden_at,omitempty" db:"hidden_at"`
+	// Shadowed messages were sent while shadow muted and are only shown to their author
+	Shadowed bool `json:"-" db:"shadowed"`
+}
+
+// SystemMessageKind is what a system message announces
+type SystemMessageKind string
+
+const (
+	// SystemMessageTileConfirmed announces a tile confirmed by a host or a timer
+	SystemMessageTileConfirmed SystemMessageKind = "tile_confirmed"
+	// SystemMessageWinner announces a player who got bingo
+	SystemMessageWinner SystemMessageKind = "winner"
+	// SystemMessageTimerExpired is the message a timer was set up to post when it expires
+	SystemMessageTimerExpired SystemMessageKind = "timer_expired"
+	// SystemMessageAnnouncement is free text posted by a host or a chat command
+	SystemMessageAnnouncement SystemMessageKind = "announcement"
+)
+
+// TranscriptEntry is a message of a show as exported in its chat transcript
+type TranscriptEntry struct {
+	ID           string             `json:"id" db:"id"`
+	PlayerID     string             `json:"player_id" db:"player_id"`
+	DisplayName  *string            `json:"display_name" db:"display_name"`
+	Contents     string             `json:"contents" db:"contents"`
+	System       bool               `json:"system" db:"system"`
+	Kind         *SystemMessageKind `json:"kind" db:"kind"`
+	Replying     *string            `json:"replying" db:"replying"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+	EditedAt     *time.Time         `json:"edited_at" db:"edited_at"`
+	DeletedAt    *time.Time         `json:"deleted_at" db:"deleted_at"`
+	DeletedBy    *string            `json:"deleted_by" db:"deleted_by"`
+	DeleteReason *string            `json:"delete_reason" db:"delete_reason"`
+	Hidden       bool               `json:"hidden" db:"hidden"`
+	Shadowed     bool               `json:"shadowed" db:"shadowed"`
+	// OffsetSeconds is how long after the show started the message was sent, negative for
+	// pre-show chat and null when the show has no start time
+	OffsetSeconds *int64 `json:"offset_seconds" db:"offset_seconds"`
+}
+
+// MessageRevision keeps the contents a message had before it was edited
+type MessageRevision struct {
+	ID        string    `json:"id" db:"id"`
+	MessageID string    `json:"message_id" db:"message_id"`
+	Contents  string    `json:"contents" db:"contents"`
+	EditedBy  *string   `json:"edited_by" db:"edited_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// DeleteMessageRequest carries the optional reason given when deleting messages
+type DeleteMessageRequest struct {
+	Reason string `json:"reason"`
 }
 
-// Timer represents a countdown timer for shows
+// TimerMode selects whether a timer counts down to an expiry or up from its start
+type TimerMode string
+
+const (
+	TimerModeCountdown TimerMode = "countdown"
+	TimerModeStopwatch TimerMode = "stopwatch"
+)
+
+// TimerVisibility controls which SSE hubs receive a timer's lifecycle events
+type TimerVisibility string
+
+const (
+	TimerVisibilityPublic TimerVisibility = "public"
+	TimerVisibilityHost   TimerVisibility = "host"
+)
+
+// Timer represents a countdown or stopwatch timer for shows
 type Timer struct {
-	ID        string                 `json:"id" db:"id"`
-	Title     string                 `json:"title" db:"title"`
-	Duration  int                    `json:"duration" db:"duration"`
-	CreatedBy *string                `json:"created_by" db:"created_by"`
-	ShowID    *string                `json:"show_id" db:"show_id"`
-	StartsAt  *time.Time             `json:"starts_at" db:"starts_at"`
-	ExpiresAt *time.Time             `json:"expires_at" db:"expires_at"`
-	IsActive  bool                   `json:"is_active" db:"is_active"`
-	Settings  map[string]interface{} `json:"settings" db:"settings"`
-	CreatedAt time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at" db:"updated_at"`
-	DeletedAt *time.Time             `json:"deleted_at" db:"deleted_at"`
+	ID          string                 `json:"id" db:"id"`
+	Title       string                 `json:"title" db:"title"`
+	Mode        TimerMode              `json:"mode" db:"mode"`
+	Visibility  TimerVisibility        `json:"visibility" db:"visibility"`
+	Duration    int                    `json:"duration" db:"duration"`
+	CreatedBy   *string                `json:"created_by" db:"created_by"`
+	ShowID      *string                `json:"show_id" db:"show_id"`
+	StartsAt    *time.Time             `json:"starts_at" db:"starts_at"`
+	ExpiresAt   *time.Time             `json:"expires_at" db:"expires_at"`
+	IsActive    bool                   `json:"is_active" db:"is_active"`
+	Settings    map[string]interface{} `json:"settings" db:"settings"`
+	PausedAt    *time.Time             `json:"paused_at" db:"paused_at"`
+	RemainingMs *int64                 `json:"remaining_ms" db:"remaining_ms"`
+	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time             `json:"deleted_at" db:"deleted_at"`
+}
+
+// TimerActionType identifies what a timer does when it expires
+type TimerActionType string
+
+const (
+	TimerActionConfirmTile   TimerActionType = "confirm_tile"
+	TimerActionSystemMessage TimerActionType = "system_message"
+	TimerActionHostEvent     TimerActionType = "host_event"
+)
+
+// TimerAction is a single step executed by the timer monitor when a timer expires.
+// Actions are stored as a list under the "actions" key of Timer.Settings.
+type TimerAction struct {
+	Type    TimerActionType        `json:"type"`
+	TileID  string                 `json:"tile_id,omitempty"`
+	Context string                 `json:"context,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Event   string                 `json:"event,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// TimerLap is a lap marker recorded on a running stopwatch
+type TimerLap struct {
+	ID         string    `json:"id" db:"id"`
+	TimerID    string    `json:"timer_id" db:"timer_id"`
+	ShowID     *string   `json:"show_id" db:"show_id"`
+	Label      *string   `json:"label" db:"label"`
+	ElapsedMs  int64     `json:"elapsed_ms" db:"elapsed_ms"`
+	RecordedBy *string   `json:"recorded_by" db:"recorded_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// TimerResult records the final value of a stopwatch when it is stopped
+type TimerResult struct {
+	ID        string    `json:"id" db:"id"`
+	TimerID   string    `json:"timer_id" db:"timer_id"`
+	ShowID    *string   `json:"show_id" db:"show_id"`
+	Title     string    `json:"title" db:"title"`
+	ElapsedMs int64     `json:"elapsed_ms" db:"elapsed_ms"`
+	Laps      int       `json:"laps" db:"laps"`
+	StoppedBy *string   `json:"stopped_by" db:"stopped_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
 // MessageRequest is used in the API to parse successfully
 type MessageRequest struct {
-	Contents string `json:"contents" db:"contents"`
+	Contents string  `json:"contents" db:"contents"`
+	Replying *string `json:"replying" db:"replying"`
+}
+
+// Whisper is a private message between two players
+type Whisper struct {
+	ID          string     `json:"id" db:"id"`
+	ShowID      *string    `json:"show_id" db:"show_id"`
+	SenderID    string     `json:"sender_id" db:"sender_id"`
+	RecipientID string     `json:"recipient_id" db:"recipient_id"`
+	Contents    string     `json:"contents" db:"contents"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at" db:"deleted_at"`
+	// Shadowed whispers were sent while shadow muted and never reach the recipient
+	Shadowed bool `json:"-" db:"shadowed"`
+}
+
+// WhisperRequest is used to parse whispers sent to a player ID or display name
+type WhisperRequest struct {
+	Recipient string `json:"recipient"`
+	Contents  string `json:"contents"`
+}
+
+// ChatSettings holds the chat restrictions hosts have set for a show
+type ChatSettings struct {
+	ShowID               string     `json:"show_id" db:"show_id"`
+	SlowModeSeconds      int        `json:"slow_mode_seconds" db:"slow_mode_seconds"`
+	MinAccountAgeMinutes int        `json:"min_account_age_minutes" db:"min_account_age_minutes"`
+	UpdatedBy            *string    `json:"updated_by" db:"updated_by"`
+	UpdatedAt            *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ReactionEmojis is the fixed set of emoji players can react to messages with
+var ReactionEmojis = []string{"👍", "❤️", "😂", "😮", "😢", "🔥", "🎉", "👀"}
+
+// ReactionRequest is used to parse a reaction added to or removed from a message
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+// MessagePreview is a trimmed copy of a message shown alongside its replies
+type MessagePreview struct {
+	ID       string `json:"id"`
+	PlayerID string `json:"player_id"`
+	Contents string `json:"contents"`
+	System   bool   `json:"system"`
 }
 
 // TileSuggestion represents a user-submitted tile suggestion
@@ -163,3 +335,213 @@ type TileSuggestion struct {
 	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt  *time.Time `json:"deleted_at" db:"deleted_at"`
 }
+
+// SanctionType is the kind of punishment a sanction applies
+type SanctionType string
+
+const (
+	// SanctionMute stops the player from posting
+	SanctionMute SanctionType = "mute"
+	// SanctionShadowMute lets the player keep posting in chat, but only they and moderators
+	// see what they post
+	SanctionShadowMute SanctionType = "shadow_mute"
+	// SanctionKick disconnects the player and keeps them out until it expires
+	SanctionKick SanctionType = "kick"
+	// SanctionBan disconnects the player and stops them from connecting or posting
+	SanctionBan SanctionType = "ban"
+)
+
+// SanctionScope is the part of the site a sanction applies to
+type SanctionScope string
+
+const (
+	SanctionScopeChat        SanctionScope = "chat"
+	SanctionScopeSuggestions SanctionScope = "suggestions"
+	SanctionScopeGlobal      SanctionScope = "global"
+)
+
+// Sanction records a mute, kick or ban issued against a player
+type Sanction struct {
+	ID        string        `json:"id" db:"id"`
+	PlayerID  string        `json:"player_id" db:"player_id"`
+	Type      SanctionType  `json:"type" db:"type"`
+	Scope     SanctionScope `json:"scope" db:"scope"`
+	Reason    *string       `json:"reason" db:"reason"`
+	IssuedBy  *string       `json:"issued_by" db:"issued_by"`
+	ExpiresAt *time.Time    `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time    `json:"revoked_at" db:"revoked_at"`
+	RevokedBy *string       `json:"revoked_by" db:"revoked_by"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// SanctionRequest is used to parse sanctions issued by moderators
+type SanctionRequest struct {
+	Player          string        `json:"player"`
+	Type            SanctionType  `json:"type"`
+	Scope           SanctionScope `json:"scope"`
+	Reason          string        `json:"reason"`
+	DurationSeconds int           `json:"duration_seconds"`
+}
+
+// ModerationRuleKind is what a moderation rule's pattern is
+type ModerationRuleKind string
+
+const (
+	// ModerationRuleTerm is a banned word or phrase, matched on word boundaries
+	ModerationRuleTerm ModerationRuleKind = "term"
+	// ModerationRuleRegex is a case-insensitive regular expression matched on the raw message
+	ModerationRuleRegex ModerationRuleKind = "regex"
+	// ModerationRuleAllow is a phrase that is never treated as a banned term
+	ModerationRuleAllow ModerationRuleKind = "allow"
+)
+
+// ModerationRule is a blocked term, regex rule or allowlisted phrase managed by moderators
+type ModerationRule struct {
+	ID        string             `json:"id" db:"id"`
+	Kind      ModerationRuleKind `json:"kind" db:"kind"`
+	Pattern   string             `json:"pattern" db:"pattern"`
+	RuleID    string             `json:"rule_id" db:"rule_id"`
+	Reason    *string            `json:"reason" db:"reason"`
+	Enabled   bool               `json:"enabled" db:"enabled"`
+	CreatedBy *string            `json:"created_by" db:"created_by"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// ModerationRuleRequest is used to parse moderation rules created or changed by moderators
+type ModerationRuleRequest struct {
+	Kind    ModerationRuleKind `json:"kind"`
+	Pattern string             `json:"pattern"`
+	RuleID  string             `json:"rule_id"`
+	Reason  string             `json:"reason"`
+	Enabled *bool              `json:"enabled"`
+}
+
+// ModerationThresholds are the limits of the caps and repeated character checks
+type ModerationThresholds struct {
+	CapsRatio     float64    `json:"caps_ratio" db:"caps_ratio"`
+	CapsMinLength int        `json:"caps_min_length" db:"caps_min_length"`
+	RepeatLimit   int        `json:"repeat_limit" db:"repeat_limit"`
+	UpdatedBy     *string    `json:"updated_by" db:"updated_by"`
+	UpdatedAt     *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ModerationRuleHit counts how often a rule rejected a message
+type ModerationRuleHit struct {
+	RuleID    string     `json:"rule_id" db:"rule_id"`
+	Match     string     `json:"match" db:"match"`
+	Hits      int64      `json:"hits" db:"hits"`
+	LastHitAt *time.Time `json:"last_hit_at" db:"last_hit_at"`
+}
+
+// ReportCategory is the kind of problem a player reported
+type ReportCategory string
+
+const (
+	ReportSpam       ReportCategory = "spam"
+	ReportHarassment ReportCategory = "harassment"
+	ReportHate       ReportCategory = "hate"
+	ReportSexual     ReportCategory = "sexual"
+	ReportSpoilers   ReportCategory = "spoilers"
+	ReportOther      ReportCategory = "other"
+)
+
+// ReportStatus is whether a report still waits for a moderator
+type ReportStatus string
+
+const (
+	ReportOpen     ReportStatus = "open"
+	ReportResolved ReportStatus = "resolved"
+)
+
+// ReportAction is what a moderator did about a report when resolving it
+type ReportAction string
+
+const (
+	// ReportDismiss closes the report without acting, restoring a hidden message
+	ReportDismiss ReportAction = "dismiss"
+	// ReportDelete deletes the reported message
+	ReportDelete ReportAction = "delete"
+	ReportMute   ReportAction = "mute"
+	ReportKick   ReportAction = "kick"
+	ReportBan    ReportAction = "ban"
+)
+
+// Report is a complaint filed by a player about a message or another player
+type Report struct {
+	ID         string         `json:"id" db:"id"`
+	ReporterID string         `json:"reporter_id" db:"reporter_id"`
+	PlayerID   string         `json:"player_id" db:"player_id"`
+	MessageID  *string        `json:"message_id" db:"message_id"`
+	Category   ReportCategory `json:"category" db:"category"`
+	Details    *string        `json:"details" db:"details"`
+	Status     ReportStatus   `json:"status" db:"status"`
+	Action     *ReportAction  `json:"action" db:"action"`
+	SanctionID *string        `json:"sanction_id" db:"sanction_id"`
+	ResolvedBy *string        `json:"resolved_by" db:"resolved_by"`
+	ResolvedAt *time.Time     `json:"resolved_at" db:"resolved_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ReportRequest is used to parse reports filed by players
+type ReportRequest struct {
+	Category ReportCategory `json:"category"`
+	Details  string         `json:"details"`
+}
+
+// ReportGroup collects the open reports against the same message or player for review
+type ReportGroup struct {
+	PlayerID   string                 `json:"player_id"`
+	MessageID  *string                `json:"message_id"`
+	Message    *Message               `json:"message,omitempty"`
+	Count      int                    `json:"count"`
+	Categories map[ReportCategory]int `json:"categories"`
+	Reports    []Report               `json:"reports"`
+}
+
+// ResolveReportRequest is used to parse a moderator's decision on reports
+type ResolveReportRequest struct {
+	Action          ReportAction  `json:"action"`
+	Scope           SanctionScope `json:"scope"`
+	Reason          string        `json:"reason"`
+	DurationSeconds int           `json:"duration_seconds"`
+}
+
+// AuditEntry records a privileged action, who took it and what it changed
+type AuditEntry struct {
+	ID         string          `json:"id" db:"id"`
+	ActorID    *string         `json:"actor_id" db:"actor_id"`
+	Action     string          `json:"action" db:"action"`
+	TargetType string          `json:"target_type" db:"target_type"`
+	TargetID   *string         `json:"target_id" db:"target_id"`
+	Before     json.RawMessage `json:"before" db:"before"`
+	After      json.RawMessage `json:"after" db:"after"`
+	Method     *string         `json:"method" db:"method"`
+	Path       *string         `json:"path" db:"path"`
+	IP         *string         `json:"ip" db:"ip"`
+	UserAgent  *string         `json:"user_agent" db:"user_agent"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter narrows down the audit log entries listed, empty fields match everything
+type AuditFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Since      *time.Time
+	Until      *time.Time
+	// Before is the ID of an entry, only older entries are listed
+	Before string
+	Limit  int
+}
+
+// Strike records a message from a player that automatic moderation blocked
+type Strike struct {
+	ID        string    `json:"id" db:"id"`
+	PlayerID  string    `json:"player_id" db:"player_id"`
+	RuleID    string    `json:"rule_id" db:"rule_id"`
+	Stage     string    `json:"stage" db:"stage"`
+	Match     *string   `json:"match" db:"match"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
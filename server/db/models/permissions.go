@@ -108,7 +108,8 @@ func AdminPermissions() Permission {
 		PermCanSuggestTiles |
 		PermCanReviewTiles |
 		PermCanApproveTiles |
-		PermCanManageTiles
+		PermCanManageTiles |
+		PermCanManageTimers
 }
 
 // HasPermission checks if a permission set contains a specific permission
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/matoous/go-nanoid/v2"
+)
+
+// GetModerationRules retrieves every moderation rule, enabled or not
+func GetModerationRules(ctx context.Context) ([]models.ModerationRule, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, kind, pattern, rule_id, reason, enabled, created_by, created_at, updated_at
+		FROM moderation_rules
+		ORDER BY kind, rule_id, pattern
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.ModerationRule])
+}
+
+// GetModerationRuleByID retrieves a moderation rule by ID
+func GetModerationRuleByID(ctx context.Context, id string) (*models.ModerationRule, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, kind, pattern, rule_id, reason, enabled, created_by, created_at, updated_at
+		FROM moderation_rules
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[models.ModerationRule])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("moderation rule not found")
+		}
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// PersistModerationRule inserts a moderation rule when it has no ID yet, or updates it
+func PersistModerationRule(ctx context.Context, rule *models.ModerationRule) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	if rule.ID == "" {
+		rule.ID, _ = gonanoid.New(10)
+		return pool.QueryRow(ctx, `
+			INSERT INTO moderation_rules (id, kind, pattern, rule_id, reason, enabled, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at, updated_at
+		`, rule.ID, rule.Kind, rule.Pattern, rule.RuleID, rule.Reason, rule.Enabled, rule.CreatedBy).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	}
+
+	return pool.QueryRow(ctx, `
+		UPDATE moderation_rules
+		SET kind = $2, pattern = $3, rule_id = $4, reason = $5, enabled = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`, rule.ID, rule.Kind, rule.Pattern, rule.RuleID, rule.Reason, rule.Enabled).Scan(&rule.UpdatedAt)
+}
+
+// DeleteModerationRule removes a moderation rule
+func DeleteModerationRule(ctx context.Context, id string) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	tag, err := pool.Exec(ctx, `DELETE FROM moderation_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("moderation rule not found")
+	}
+	return nil
+}
+
+// GetModerationThresholds retrieves the caps and repeated character limits
+func GetModerationThresholds(ctx context.Context) (*models.ModerationThresholds, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT caps_ratio, caps_min_length, repeat_limit, updated_by, updated_at
+		FROM moderation_thresholds
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[models.ModerationThresholds])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("moderation thresholds not found")
+		}
+		return nil, err
+	}
+
+	return thresholds, nil
+}
+
+// PersistModerationThresholds saves the caps and repeated character limits
+func PersistModerationThresholds(ctx context.Context, thresholds *models.ModerationThresholds) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	return pool.QueryRow(ctx, `
+		INSERT INTO moderation_thresholds (id, caps_ratio, caps_min_length, repeat_limit, updated_by)
+		VALUES (TRUE, $1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE
+		SET caps_ratio = EXCLUDED.caps_ratio,
+		    caps_min_length = EXCLUDED.caps_min_length,
+		    repeat_limit = EXCLUDED.repeat_limit,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`, thresholds.CapsRatio, thresholds.CapsMinLength, thresholds.RepeatLimit, thresholds.UpdatedBy).Scan(&thresholds.UpdatedAt)
+}
+
+// RecordModerationRuleHits adds hits to the counts of rules
+func RecordModerationRuleHits(ctx context.Context, hits []models.ModerationRuleHit) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	batch := &pgx.Batch{}
+	for _, hit := range hits {
+		batch.Queue(`
+			INSERT INTO moderation_rule_hits (rule_id, match, hits, last_hit_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (rule_id, match) DO UPDATE
+			SET hits = moderation_rule_hits.hits + EXCLUDED.hits,
+			    last_hit_at = EXCLUDED.last_hit_at
+		`, hit.RuleID, hit.Match, hit.Hits, hit.LastHitAt)
+	}
+
+	return pool.SendBatch(ctx, batch).Close()
+}
+
+// GetModerationRuleHits retrieves the hit counts of every rule, most hit first
+func GetModerationRuleHits(ctx context.Context) ([]models.ModerationRuleHit, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT rule_id, match, hits, last_hit_at
+		FROM moderation_rule_hits
+		ORDER BY hits DESC, rule_id, match
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.ModerationRuleHit])
+}
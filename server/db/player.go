@@ -353,6 +353,80 @@ func GetPlayerByIdentifier(ctx context.Context, identifier string, tx ...pgx.Tx)
 	return &player, nil
 }
 
+// GetPlayersByIDs returns the players with the given IDs, keyed by ID
+func GetPlayersByIDs(ctx context.Context, ids []string) (map[string]models.Player, error) {
+	players := make(map[string]models.Player, len(ids))
+	if len(ids) == 0 {
+		return players, nil
+	}
+
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, did, display_name, avatar, settings, score, permissions, created_at, updated_at, deleted_at
+		FROM players
+		WHERE id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var player models.Player
+		err := rows.Scan(
+			&player.ID, &player.DID, &player.DisplayName, &player.Avatar, &player.Settings, &player.Score, &player.Permissions,
+			&player.CreatedAt, &player.UpdatedAt, &player.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		players[player.ID] = player
+	}
+
+	return players, rows.Err()
+}
+
+// GetPlayersByDisplayNames returns the players whose display names match, ignoring case
+func GetPlayersByDisplayNames(ctx context.Context, names []string) ([]models.Player, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, did, display_name, avatar, settings, score, permissions, created_at, updated_at, deleted_at
+		FROM players
+		WHERE LOWER(display_name) = ANY($1) AND deleted_at IS NULL
+	`, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []models.Player
+	for rows.Next() {
+		var player models.Player
+		err := rows.Scan(
+			&player.ID, &player.DID, &player.DisplayName, &player.Avatar, &player.Settings, &player.Score, &player.Permissions,
+			&player.CreatedAt, &player.UpdatedAt, &player.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+
+	return players, rows.Err()
+}
+
 // GetAllPlayers returns all players (for admin purposes, might want to add pagination later)
 func GetAllPlayers(ctx context.Context, tx ...pgx.Tx) ([]models.Player, error) {
 	var rows pgx.Rows
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddReaction records a player's reaction to a message, returning false if they had already reacted with that emoji.
+// Shadowed reactions are only counted for the player who added them.
+func AddReaction(ctx context.Context, messageID string, playerID string, emoji string, shadowed bool) (bool, error) {
+	pool := Pool()
+	if pool == nil {
+		return false, errors.New("database not available")
+	}
+
+	result, err := pool.Exec(ctx, `
+		INSERT INTO message_reactions (message_id, player_id, emoji, shadowed)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`, messageID, playerID, emoji, shadowed)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// RemoveReaction removes a player's reaction from a message, returning false if there was none,
+// and whether the removed reaction was shadowed
+func RemoveReaction(ctx context.Context, messageID string, playerID string, emoji string) (bool, bool, error) {
+	pool := Pool()
+	if pool == nil {
+		return false, false, errors.New("database not available")
+	}
+
+	var shadowed bool
+	err := pool.QueryRow(ctx, `
+		DELETE FROM message_reactions WHERE message_id = $1 AND player_id = $2 AND emoji = $3
+		RETURNING shadowed
+	`, messageID, playerID, emoji).Scan(&shadowed)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+
+	return true, shadowed, nil
+}
+
+// GetReactionCounts returns how many players reacted with each emoji, keyed by message ID then emoji.
+// Shadowed reactions are only counted when viewerID added them.
+func GetReactionCounts(ctx context.Context, messageIDs []string, viewerID string) (map[string]map[string]int, error) {
+	counts := make(map[string]map[string]int)
+	if len(messageIDs) == 0 {
+		return counts, nil
+	}
+
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT message_id, emoji, COUNT(*)
+		FROM message_reactions
+		WHERE message_id = ANY($1) AND (NOT shadowed OR player_id = $2)
+		GROUP BY message_id, emoji
+	`, messageIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageID, emoji string
+		var count int
+		if err := rows.Scan(&messageID, &emoji, &count); err != nil {
+			return nil, err
+		}
+		if counts[messageID] == nil {
+			counts[messageID] = make(map[string]int)
+		}
+		counts[messageID][emoji] = count
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/matoous/go-nanoid/v2"
+)
+
+const reportColumns = `id, reporter_id, player_id, message_id, category, details, status, action, sanction_id, resolved_by, resolved_at, created_at`
+
+// PersistReport files a new report, returning false if the reporter already has an open
+// report against the same message or player
+func PersistReport(ctx context.Context, report *models.Report) (bool, error) {
+	pool := Pool()
+	if pool == nil {
+		return false, errors.New("database not available")
+	}
+
+	if report.ID == "" {
+		report.ID, _ = gonanoid.New(10)
+	}
+	report.Status = models.ReportOpen
+
+	err := pool.QueryRow(ctx, `
+		INSERT INTO reports (id, reporter_id, player_id, message_id, category, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+		RETURNING created_at
+	`, report.ID, report.ReporterID, report.PlayerID, report.MessageID, report.Category, report.Details).Scan(&report.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetReportByID retrieves a report by ID
+func GetReportByID(ctx context.Context, id string) (*models.Report, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT `+reportColumns+`
+		FROM reports
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[models.Report])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("report not found")
+		}
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetOpenReports retrieves every report waiting for a moderator, oldest first
+func GetOpenReports(ctx context.Context) ([]models.Report, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT `+reportColumns+`
+		FROM reports
+		WHERE status = 'open'
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Report])
+}
+
+// CountOpenMessageReports counts the players with an open report against a message
+func CountOpenMessageReports(ctx context.Context, messageID string) (int, error) {
+	pool := Pool()
+	if pool == nil {
+		return 0, errors.New("database not available")
+	}
+
+	var count int
+	err := pool.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT reporter_id) FROM reports WHERE message_id = $1 AND status = 'open'
+	`, messageID).Scan(&count)
+	return count, err
+}
+
+// ResolveReports closes every open report against the same message or player as the given
+// report, recording the action taken, and returns the IDs of the reports closed
+func ResolveReports(ctx context.Context, report *models.Report, action models.ReportAction, sanctionID *string, resolvedBy string) ([]string, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		UPDATE reports
+		SET status = 'resolved', action = $3, sanction_id = $4, resolved_by = $5, resolved_at = CURRENT_TIMESTAMP
+		WHERE status = 'open' AND player_id = $1 AND message_id IS NOT DISTINCT FROM $2
+		RETURNING id
+	`, report.PlayerID, report.MessageID, action, sanctionID, resolvedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
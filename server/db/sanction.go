@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/matoous/go-nanoid/v2"
+)
+
+// PersistSanction saves a new sanction
+func PersistSanction(ctx context.Context, sanction *models.Sanction) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	if sanction.ID == "" {
+		sanction.ID, _ = gonanoid.New(10)
+	}
+
+	return pool.QueryRow(ctx, `
+		INSERT INTO sanctions (id, player_id, type, scope, reason, issued_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`, sanction.ID, sanction.PlayerID, sanction.Type, sanction.Scope, sanction.Reason, sanction.IssuedBy, sanction.ExpiresAt).Scan(&sanction.CreatedAt)
+}
+
+// GetSanctionByID retrieves a sanction by ID
+func GetSanctionByID(ctx context.Context, id string) (*models.Sanction, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, player_id, type, scope, reason, issued_by, expires_at, revoked_at, revoked_by, created_at
+		FROM sanctions
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sanction, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[models.Sanction])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("sanction not found")
+		}
+		return nil, err
+	}
+
+	return sanction, nil
+}
+
+// GetActiveSanctions retrieves the sanctions of a player which have neither expired nor been
+// revoked, or of every player when playerID is empty
+func GetActiveSanctions(ctx context.Context, playerID string) ([]models.Sanction, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, player_id, type, scope, reason, issued_by, expires_at, revoked_at, revoked_by, created_at
+		FROM sanctions
+		WHERE ($1 = '' OR player_id = $1)
+		  AND revoked_at IS NULL
+		  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		ORDER BY created_at DESC
+	`, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Sanction])
+}
+
+// GetActiveSanction returns the most recent active sanction of one of the given types which
+// applies to scope, or nil if the player is not sanctioned
+func GetActiveSanction(ctx context.Context, playerID string, scope models.SanctionScope, types ...models.SanctionType) (*models.Sanction, error) {
+	sanctions, err := GetActiveSanctions(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sanctions {
+		if !sanctions[i].Scope.Covers(scope) {
+			continue
+		}
+		for _, t := range types {
+			if sanctions[i].Type == t {
+				return &sanctions[i], nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// RevokeSanction lifts a sanction before it expires
+func RevokeSanction(ctx context.Context, id string, revokedBy string) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	result, err := pool.Exec(ctx, `
+		UPDATE sanctions
+		SET revoked_at = CURRENT_TIMESTAMP, revoked_by = $2
+		WHERE id = $1 AND revoked_at IS NULL
+	`, id, revokedBy)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("sanction not found")
+	}
+
+	return nil
+}
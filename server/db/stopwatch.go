@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/matoous/go-nanoid/v2"
+)
+
+// RecordTimerLap saves a lap marker for a stopwatch timer
+func RecordTimerLap(ctx context.Context, lap *models.TimerLap) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	if lap.ID == "" {
+		lap.ID, _ = gonanoid.New(10)
+	}
+
+	return pool.QueryRow(ctx, `
+		INSERT INTO timer_laps (id, timer_id, show_id, label, elapsed_ms, recorded_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, lap.ID, lap.TimerID, lap.ShowID, lap.Label, lap.ElapsedMs, lap.RecordedBy).Scan(&lap.CreatedAt)
+}
+
+// GetTimerLaps retrieves the lap markers of a timer in the order they were recorded
+func GetTimerLaps(ctx context.Context, timerID string) ([]models.TimerLap, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, timer_id, show_id, label, elapsed_ms, recorded_by, created_at
+		FROM timer_laps
+		WHERE timer_id = $1
+		ORDER BY elapsed_ms
+	`, timerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.TimerLap])
+}
+
+// StopStopwatch stops a running stopwatch and records its final elapsed time for the show
+func StopStopwatch(ctx context.Context, timerID string, stoppedBy *string) (*models.TimerResult, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	result := &models.TimerResult{
+		TimerID:   timerID,
+		StoppedBy: stoppedBy,
+	}
+
+	var startsAt *time.Time
+	err = tx.QueryRow(ctx, `
+		UPDATE timers
+		SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND mode = 'stopwatch' AND is_active = true AND deleted_at IS NULL
+		RETURNING show_id, title, starts_at
+	`, timerID).Scan(&result.ShowID, &result.Title, &startsAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("stopwatch is not running")
+		}
+		return nil, err
+	}
+
+	if startsAt != nil {
+		result.ElapsedMs = now.Sub(*startsAt).Milliseconds()
+	}
+
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM timer_laps WHERE timer_id = $1`, timerID).Scan(&result.Laps)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ID, _ = gonanoid.New(10)
+	err = tx.QueryRow(ctx, `
+		INSERT INTO timer_results (id, timer_id, show_id, title, elapsed_ms, laps, stopped_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`, result.ID, result.TimerID, result.ShowID, result.Title, result.ElapsedMs, result.Laps, result.StoppedBy).Scan(&result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetTimerResultsForShow retrieves the recorded stopwatch results of a show. Results of host-only
+// timers are left out unless includeHost is set.
+func GetTimerResultsForShow(ctx context.Context, showID string, includeHost bool) ([]models.TimerResult, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT r.id, r.timer_id, r.show_id, r.title, r.elapsed_ms, r.laps, r.stopped_by, r.created_at
+		FROM timer_results r
+		JOIN timers t ON t.id = r.timer_id
+		WHERE r.show_id = $1 AND ($2 OR t.visibility = 'public')
+		ORDER BY r.created_at
+	`, showID, includeHost)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.TimerResult])
+}
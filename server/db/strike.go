@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/matoous/go-nanoid/v2"
+)
+
+// PersistStrike records a strike against a player
+func PersistStrike(ctx context.Context, strike *models.Strike) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	if strike.ID == "" {
+		strike.ID, _ = gonanoid.New(10)
+	}
+
+	return pool.QueryRow(ctx, `
+		INSERT INTO moderation_strikes (id, player_id, rule_id, stage, match)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, strike.ID, strike.PlayerID, strike.RuleID, strike.Stage, strike.Match).Scan(&strike.CreatedAt)
+}
+
+// GetStrikesSince retrieves the strikes of a player recorded after since, oldest first
+func GetStrikesSince(ctx context.Context, playerID string, since time.Time) ([]models.Strike, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, player_id, rule_id, stage, match, created_at
+		FROM moderation_strikes
+		WHERE player_id = $1 AND created_at > $2
+		ORDER BY created_at
+	`, playerID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Strike])
+}
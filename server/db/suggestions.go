@@ -88,6 +88,37 @@ func GetTileSuggestions(ctx context.Context, status *string) ([]models.TileSugge
 	return suggestions, nil
 }
 
+// GetTileSuggestionByID retrieves a tile suggestion by ID, returning nil if it does not exist
+func GetTileSuggestionByID(ctx context.Context, id string) (*models.TileSuggestion, error) {
+	query := `
+		SELECT id, name, tile_name, reason, status, reviewed_by, reviewed_at, created_at, updated_at, deleted_at
+		FROM tile_suggestions
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var suggestion models.TileSuggestion
+	err := Pool().QueryRow(ctx, query, id).Scan(
+		&suggestion.ID,
+		&suggestion.Name,
+		&suggestion.TileName,
+		&suggestion.Reason,
+		&suggestion.Status,
+		&suggestion.ReviewedBy,
+		&suggestion.ReviewedAt,
+		&suggestion.CreatedAt,
+		&suggestion.UpdatedAt,
+		&suggestion.DeletedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	return &suggestion, nil
+}
+
 // UpdateTileSuggestion updates a tile suggestion's status and review info
 func UpdateTileSuggestion(ctx context.Context, id, status string, reviewedBy *string) (*models.TileSuggestion, error) {
 	now := time.Now()
@@ -10,25 +10,66 @@ import (
 	"github.com/matoous/go-nanoid/v2"
 )
 
+// TimerScheduleListener is notified whenever a timer's expiry may have changed.
+// expiresAt is nil when the timer should no longer fire.
+type TimerScheduleListener func(timerID string, expiresAt *time.Time)
+
+var timerScheduleListeners []TimerScheduleListener
+
+// OnTimerScheduleChange registers a listener for timer schedule changes
+func OnTimerScheduleChange(listener TimerScheduleListener) {
+	timerScheduleListeners = append(timerScheduleListeners, listener)
+}
+
+// notifyTimerSchedule informs the registered listeners about a timer's new expiry
+func notifyTimerSchedule(timerID string, expiresAt *time.Time) {
+	for _, listener := range timerScheduleListeners {
+		listener(timerID, expiresAt)
+	}
+}
+
+// scheduledExpiry returns the time a timer should fire, or nil if it should not fire
+func scheduledExpiry(timer *models.Timer) *time.Time {
+	if !timer.IsActive {
+		return nil
+	}
+	return timer.ExpiresAt
+}
+
 // PersistTimer saves or updates a Timer in the database
 func PersistTimer(ctx context.Context, timer *models.Timer, tx ...pgx.Tx) error {
+	err := persistTimer(ctx, timer, tx...)
+	if err == nil {
+		notifyTimerSchedule(timer.ID, scheduledExpiry(timer))
+	}
+	return err
+}
+
+func persistTimer(ctx context.Context, timer *models.Timer, tx ...pgx.Tx) error {
+	if timer.Mode == "" {
+		timer.Mode = models.TimerModeCountdown
+	}
+	if timer.Visibility == "" {
+		timer.Visibility = models.TimerVisibilityPublic
+	}
+
 	if len(tx) > 0 {
 		// Use transaction
 		if timer.ID == "" {
 			// New timer, generate ID and insert
 			timer.ID, _ = gonanoid.New(10)
 			_, err := tx[0].Exec(ctx, `
-				INSERT INTO timers (id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-			`, timer.ID, timer.Title, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings)
+				INSERT INTO timers (id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			`, timer.ID, timer.Title, timer.Mode, timer.Visibility, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings, timer.PausedAt, timer.RemainingMs)
 			return err
 		} else {
 			// Existing timer, update
 			_, err := tx[0].Exec(ctx, `
 				UPDATE timers
-				SET title = $1, duration = $2, created_by = $3, show_id = $4, starts_at = $5, expires_at = $6, is_active = $7, settings = $8, updated_at = CURRENT_TIMESTAMP
-				WHERE id = $9 AND deleted_at IS NULL
-			`, timer.Title, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings, timer.ID)
+				SET title = $1, mode = $2, visibility = $3, duration = $4, created_by = $5, show_id = $6, starts_at = $7, expires_at = $8, is_active = $9, settings = $10, paused_at = $11, remaining_ms = $12, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $13 AND deleted_at IS NULL
+			`, timer.Title, timer.Mode, timer.Visibility, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings, timer.PausedAt, timer.RemainingMs, timer.ID)
 			return err
 		}
 	} else {
@@ -42,17 +83,17 @@ func PersistTimer(ctx context.Context, timer *models.Timer, tx ...pgx.Tx) error
 			// New timer, generate ID and insert
 			timer.ID, _ = gonanoid.New(10)
 			_, err := pool.Exec(ctx, `
-				INSERT INTO timers (id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-			`, timer.ID, timer.Title, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings)
+				INSERT INTO timers (id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			`, timer.ID, timer.Title, timer.Mode, timer.Visibility, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings, timer.PausedAt, timer.RemainingMs)
 			return err
 		} else {
 			// Existing timer, update
 			_, err := pool.Exec(ctx, `
 				UPDATE timers
-				SET title = $1, duration = $2, created_by = $3, show_id = $4, starts_at = $5, expires_at = $6, is_active = $7, settings = $8, updated_at = CURRENT_TIMESTAMP
-				WHERE id = $9 AND deleted_at IS NULL
-			`, timer.Title, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings, timer.ID)
+				SET title = $1, mode = $2, visibility = $3, duration = $4, created_by = $5, show_id = $6, starts_at = $7, expires_at = $8, is_active = $9, settings = $10, paused_at = $11, remaining_ms = $12, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $13 AND deleted_at IS NULL
+			`, timer.Title, timer.Mode, timer.Visibility, timer.Duration, timer.CreatedBy, timer.ShowID, timer.StartsAt, timer.ExpiresAt, timer.IsActive, timer.Settings, timer.PausedAt, timer.RemainingMs, timer.ID)
 			return err
 		}
 	}
@@ -64,7 +105,7 @@ func GetTimerByID(ctx context.Context, id string, tx ...pgx.Tx) (*models.Timer,
 
 	if len(tx) > 0 {
 		row = tx[0].QueryRow(ctx, `
-			SELECT id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings, created_at, updated_at, deleted_at
+			SELECT id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
 			FROM timers
 			WHERE id = $1 AND deleted_at IS NULL
 		`, id)
@@ -74,7 +115,7 @@ func GetTimerByID(ctx context.Context, id string, tx ...pgx.Tx) (*models.Timer,
 			return nil, errors.New("database not available")
 		}
 		row = pool.QueryRow(ctx, `
-			SELECT id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings, created_at, updated_at, deleted_at
+			SELECT id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
 			FROM timers
 			WHERE id = $1 AND deleted_at IS NULL
 		`, id)
@@ -82,8 +123,8 @@ func GetTimerByID(ctx context.Context, id string, tx ...pgx.Tx) (*models.Timer,
 
 	var timer models.Timer
 	err := row.Scan(
-		&timer.ID, &timer.Title, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
-		&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings,
+		&timer.ID, &timer.Title, &timer.Mode, &timer.Visibility, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
+		&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings, &timer.PausedAt, &timer.RemainingMs,
 		&timer.CreatedAt, &timer.UpdatedAt, &timer.DeletedAt,
 	)
 
@@ -104,7 +145,7 @@ func GetActiveTimersForShow(ctx context.Context, showID string, tx ...pgx.Tx) ([
 
 	if len(tx) > 0 {
 		rows, err = tx[0].Query(ctx, `
-			SELECT id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings, created_at, updated_at, deleted_at
+			SELECT id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
 			FROM timers
 			WHERE show_id = $1 AND is_active = true AND deleted_at IS NULL
 			ORDER BY created_at DESC
@@ -115,7 +156,7 @@ func GetActiveTimersForShow(ctx context.Context, showID string, tx ...pgx.Tx) ([
 			return nil, errors.New("database not available")
 		}
 		rows, err = pool.Query(ctx, `
-			SELECT id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings, created_at, updated_at, deleted_at
+			SELECT id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
 			FROM timers
 			WHERE show_id = $1 AND is_active = true AND deleted_at IS NULL
 			ORDER BY created_at DESC
@@ -131,8 +172,8 @@ func GetActiveTimersForShow(ctx context.Context, showID string, tx ...pgx.Tx) ([
 	for rows.Next() {
 		var timer models.Timer
 		err := rows.Scan(
-			&timer.ID, &timer.Title, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
-			&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings,
+			&timer.ID, &timer.Title, &timer.Mode, &timer.Visibility, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
+			&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings, &timer.PausedAt, &timer.RemainingMs,
 			&timer.CreatedAt, &timer.UpdatedAt, &timer.DeletedAt,
 		)
 		if err != nil {
@@ -144,91 +185,232 @@ func GetActiveTimersForShow(ctx context.Context, showID string, tx ...pgx.Tx) ([
 	return timers, rows.Err()
 }
 
-// StartTimer activates a timer and sets its expiration time
+// StartTimer activates a timer and sets its expiration time.
+// Stopwatch timers are started without an expiry, and lose the laps of their previous run.
 func StartTimer(ctx context.Context, timerID string, tx ...pgx.Tx) error {
 	now := time.Now()
 
+	var row pgx.Row
 	if len(tx) > 0 {
-		_, err := tx[0].Exec(ctx, `
+		row = tx[0].QueryRow(ctx, `
+			WITH cleared_laps AS (
+				DELETE FROM timer_laps WHERE timer_id = $2
+			)
 			UPDATE timers
-			SET is_active = true, starts_at = $1, expires_at = $1 + make_interval(secs => duration), updated_at = CURRENT_TIMESTAMP
+			SET is_active = true, starts_at = $1, expires_at = CASE WHEN mode = 'stopwatch' THEN NULL ELSE $1 + make_interval(secs => duration) END, paused_at = NULL, remaining_ms = NULL, updated_at = CURRENT_TIMESTAMP
 			WHERE id = $2 AND deleted_at IS NULL
+			RETURNING expires_at
 		`, now, timerID)
-		return err
 	} else {
 		pool := Pool()
 		if pool == nil {
 			return errors.New("database not available")
 		}
-		_, err := pool.Exec(ctx, `
+		row = pool.QueryRow(ctx, `
+			WITH cleared_laps AS (
+				DELETE FROM timer_laps WHERE timer_id = $2
+			)
 			UPDATE timers
-			SET is_active = true, starts_at = $1, expires_at = $1 + make_interval(secs => duration), updated_at = CURRENT_TIMESTAMP
+			SET is_active = true, starts_at = $1, expires_at = CASE WHEN mode = 'stopwatch' THEN NULL ELSE $1 + make_interval(secs => duration) END, paused_at = NULL, remaining_ms = NULL, updated_at = CURRENT_TIMESTAMP
 			WHERE id = $2 AND deleted_at IS NULL
+			RETURNING expires_at
 		`, now, timerID)
+	}
+
+	var expiresAt *time.Time
+	err := row.Scan(&expiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
 		return err
 	}
+
+	notifyTimerSchedule(timerID, expiresAt)
+	return nil
 }
 
 // StopTimer deactivates a timer
 func StopTimer(ctx context.Context, timerID string, tx ...pgx.Tx) error {
+	var err error
 	if len(tx) > 0 {
-		_, err := tx[0].Exec(ctx, `
+		_, err = tx[0].Exec(ctx, `
 			UPDATE timers
-			SET is_active = false, updated_at = CURRENT_TIMESTAMP
+			SET is_active = false, paused_at = NULL, remaining_ms = NULL, updated_at = CURRENT_TIMESTAMP
 			WHERE id = $1 AND deleted_at IS NULL
 		`, timerID)
-		return err
 	} else {
 		pool := Pool()
 		if pool == nil {
 			return errors.New("database not available")
 		}
-		_, err := pool.Exec(ctx, `
+		_, err = pool.Exec(ctx, `
 			UPDATE timers
-			SET is_active = false, updated_at = CURRENT_TIMESTAMP
+			SET is_active = false, paused_at = NULL, remaining_ms = NULL, updated_at = CURRENT_TIMESTAMP
 			WHERE id = $1 AND deleted_at IS NULL
 		`, timerID)
+	}
+
+	if err == nil {
+		notifyTimerSchedule(timerID, nil)
+	}
+	return err
+}
+
+// PauseTimer freezes a running timer, storing its remaining duration and clearing its expiry
+func PauseTimer(ctx context.Context, timerID string) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	tag, err := pool.Exec(ctx, `
+		UPDATE timers
+		SET paused_at = $1,
+		    remaining_ms = GREATEST(FLOOR(EXTRACT(EPOCH FROM (expires_at - $1)) * 1000), 0)::BIGINT,
+		    expires_at = NULL,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND is_active = true AND paused_at IS NULL AND expires_at IS NOT NULL AND deleted_at IS NULL
+	`, time.Now(), timerID)
+	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("timer is not running")
+	}
+
+	notifyTimerSchedule(timerID, nil)
+	return nil
 }
 
-// StopActiveTimersByTitle deactivates all active timers with the given title for a show
-func StopActiveTimersByTitle(ctx context.Context, title string, showID string) error {
+// ResumeTimer restarts a paused timer, recomputing its expiry from the remaining duration
+func ResumeTimer(ctx context.Context, timerID string) error {
 	pool := Pool()
 	if pool == nil {
 		return errors.New("database not available")
 	}
-	_, err := pool.Exec(ctx, `
+
+	var expiresAt time.Time
+	err := pool.QueryRow(ctx, `
 		UPDATE timers
-		SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		SET expires_at = $1 + make_interval(secs => remaining_ms / 1000.0),
+		    paused_at = NULL,
+		    remaining_ms = NULL,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND is_active = true AND paused_at IS NOT NULL AND deleted_at IS NULL
+		RETURNING expires_at
+	`, time.Now(), timerID).Scan(&expiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errors.New("timer is not paused")
+		}
+		return err
+	}
+
+	notifyTimerSchedule(timerID, &expiresAt)
+	return nil
+}
+
+// AdjustTimer extends an active timer by the given number of seconds, or shortens it when negative.
+// Paused timers have their remaining duration adjusted instead of their expiry.
+func AdjustTimer(ctx context.Context, timerID string, seconds int) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	var expiresAt *time.Time
+	err := pool.QueryRow(ctx, `
+		UPDATE timers
+		SET expires_at = CASE WHEN paused_at IS NULL THEN expires_at + make_interval(secs => $2::INTEGER) ELSE expires_at END,
+		    remaining_ms = CASE WHEN paused_at IS NOT NULL THEN GREATEST(remaining_ms + $2::INTEGER * 1000, 0) ELSE remaining_ms END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND is_active = true AND (expires_at IS NOT NULL OR paused_at IS NOT NULL) AND deleted_at IS NULL
+		RETURNING expires_at
+	`, timerID, seconds).Scan(&expiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errors.New("timer is not active")
+		}
+		return err
+	}
+
+	notifyTimerSchedule(timerID, expiresAt)
+	return nil
+}
+
+// StopActiveTimersByTitle deactivates all active timers with the given title for a show,
+// returning the timers it stopped
+func StopActiveTimersByTitle(ctx context.Context, title string, showID string) ([]models.Timer, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+	rows, err := pool.Query(ctx, `
+		UPDATE timers
+		SET is_active = false, paused_at = NULL, remaining_ms = NULL, updated_at = CURRENT_TIMESTAMP
 		WHERE title = $1 AND show_id = $2 AND is_active = true AND deleted_at IS NULL
+		RETURNING id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
 	`, title, showID)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stopped []models.Timer
+	for rows.Next() {
+		var timer models.Timer
+		err := rows.Scan(
+			&timer.ID, &timer.Title, &timer.Mode, &timer.Visibility, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
+			&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings, &timer.PausedAt, &timer.RemainingMs,
+			&timer.CreatedAt, &timer.UpdatedAt, &timer.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stopped = append(stopped, timer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, timer := range stopped {
+		notifyTimerSchedule(timer.ID, nil)
+	}
+	return stopped, nil
 }
 
-// GetExpiredTimers retrieves timers that have expired
-func GetExpiredTimers(ctx context.Context, tx ...pgx.Tx) ([]models.Timer, error) {
-	var rows pgx.Rows
+// DeleteTimer soft deletes a timer
+func DeleteTimer(ctx context.Context, timerID string, tx ...pgx.Tx) error {
 	var err error
-
 	if len(tx) > 0 {
-		rows, err = tx[0].Query(ctx, `
-			SELECT id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings, created_at, updated_at, deleted_at
-			FROM timers
-			WHERE is_active = true AND expires_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
-		`)
+		_, err = tx[0].Exec(ctx, "UPDATE timers SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1", timerID)
 	} else {
 		pool := Pool()
 		if pool == nil {
-			return nil, errors.New("database not available")
+			return errors.New("database not available")
 		}
-		rows, err = pool.Query(ctx, `
-			SELECT id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings, created_at, updated_at, deleted_at
-			FROM timers
-			WHERE is_active = true AND expires_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
-		`)
+		_, err = pool.Exec(ctx, "UPDATE timers SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1", timerID)
+	}
+
+	if err == nil {
+		notifyTimerSchedule(timerID, nil)
+	}
+	return err
+}
+
+// GetScheduledTimers retrieves every active timer with an expiry time, across all shows
+func GetScheduledTimers(ctx context.Context) ([]models.Timer, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
 	}
 
+	rows, err := pool.Query(ctx, `
+		SELECT id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
+		FROM timers
+		WHERE is_active = true AND expires_at IS NOT NULL AND deleted_at IS NULL
+	`)
 	if err != nil {
 		return nil, err
 	}
@@ -238,8 +420,8 @@ func GetExpiredTimers(ctx context.Context, tx ...pgx.Tx) ([]models.Timer, error)
 	for rows.Next() {
 		var timer models.Timer
 		err := rows.Scan(
-			&timer.ID, &timer.Title, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
-			&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings,
+			&timer.ID, &timer.Title, &timer.Mode, &timer.Visibility, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
+			&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings, &timer.PausedAt, &timer.RemainingMs,
 			&timer.CreatedAt, &timer.UpdatedAt, &timer.DeletedAt,
 		)
 		if err != nil {
@@ -251,17 +433,33 @@ func GetExpiredTimers(ctx context.Context, tx ...pgx.Tx) ([]models.Timer, error)
 	return timers, rows.Err()
 }
 
-// DeleteTimer soft deletes a timer
-func DeleteTimer(ctx context.Context, timerID string, tx ...pgx.Tx) error {
-	if len(tx) > 0 {
-		_, err := tx[0].Exec(ctx, "UPDATE timers SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1", timerID)
-		return err
-	} else {
-		pool := Pool()
-		if pool == nil {
-			return errors.New("database not available")
+// ClaimExpiredTimer atomically deactivates a timer if it is active and has expired by now.
+// It returns nil when the timer is not due, which includes the case where another
+// instance has already claimed it, so each expiry is only handled once.
+func ClaimExpiredTimer(ctx context.Context, timerID string, now time.Time) (*models.Timer, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	var timer models.Timer
+	err := pool.QueryRow(ctx, `
+		UPDATE timers
+		SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND is_active = true AND expires_at <= $2 AND deleted_at IS NULL
+		RETURNING id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
+	`, timerID, now).Scan(
+		&timer.ID, &timer.Title, &timer.Mode, &timer.Visibility, &timer.Duration, &timer.CreatedBy, &timer.ShowID,
+		&timer.StartsAt, &timer.ExpiresAt, &timer.IsActive, &timer.Settings, &timer.PausedAt, &timer.RemainingMs,
+		&timer.CreatedAt, &timer.UpdatedAt, &timer.DeletedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
 		}
-		_, err := pool.Exec(ctx, "UPDATE timers SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1", timerID)
-		return err
+		return nil, err
 	}
+
+	return &timer, nil
 }
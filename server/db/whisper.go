@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"wanshow-bingo/db/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/matoous/go-nanoid/v2"
+)
+
+// PersistWhisper saves a new whisper
+func PersistWhisper(ctx context.Context, whisper *models.Whisper) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	if whisper.ID == "" {
+		whisper.ID, _ = gonanoid.New(10)
+	}
+
+	return pool.QueryRow(ctx, `
+		INSERT INTO whispers (id, show_id, sender_id, recipient_id, contents, shadowed)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, whisper.ID, whisper.ShowID, whisper.SenderID, whisper.RecipientID, whisper.Contents, whisper.Shadowed).Scan(&whisper.CreatedAt)
+}
+
+// GetWhispersForPlayer retrieves the most recent whispers a player sent or received, newest
+// first. Shadowed whispers are only shown to their sender.
+func GetWhispersForPlayer(ctx context.Context, playerID string, limit int) ([]models.Whisper, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT id, show_id, sender_id, recipient_id, contents, created_at, deleted_at, shadowed
+		FROM whispers
+		WHERE (sender_id = $1 OR (recipient_id = $1 AND NOT shadowed)) AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, playerID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Whisper])
+}
+
+// BlockPlayer stops blockedID from whispering playerID
+func BlockPlayer(ctx context.Context, playerID string, blockedID string) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO player_blocks (player_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, playerID, blockedID)
+	return err
+}
+
+// UnblockPlayer removes a block
+func UnblockPlayer(ctx context.Context, playerID string, blockedID string) error {
+	pool := Pool()
+	if pool == nil {
+		return errors.New("database not available")
+	}
+
+	_, err := pool.Exec(ctx, `
+		DELETE FROM player_blocks WHERE player_id = $1 AND blocked_id = $2
+	`, playerID, blockedID)
+	return err
+}
+
+// GetBlockedPlayerIDs retrieves the IDs of the players a player has blocked
+func GetBlockedPlayerIDs(ctx context.Context, playerID string) ([]string, error) {
+	pool := Pool()
+	if pool == nil {
+		return nil, errors.New("database not available")
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT blocked_id FROM player_blocks WHERE player_id = $1 ORDER BY created_at
+	`, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// IsBlockedBetween reports whether either player has blocked the other
+func IsBlockedBetween(ctx context.Context, playerID string, otherID string) (bool, error) {
+	pool := Pool()
+	if pool == nil {
+		return false, errors.New("database not available")
+	}
+
+	var blocked bool
+	err := pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM player_blocks
+			WHERE (player_id = $1 AND blocked_id = $2) OR (player_id = $2 AND blocked_id = $1)
+		)
+	`, playerID, otherID).Scan(&blocked)
+	return blocked, err
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+)
+
+// parseTime reads an optional RFC 3339 timestamp from the query string
+func parseTime(c *fiber.Ctx, key string) (*time.Time, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
+// GetEntries lists audit log entries, newest first, filtered by the query parameters
+func GetEntries(c *fiber.Ctx) error {
+	filter := models.AuditFilter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		Before:     c.Query("before"),
+		Limit:      c.QueryInt("limit", defaultAuditPageSize),
+	}
+	if filter.Limit < 1 || filter.Limit > maxAuditPageSize {
+		filter.Limit = defaultAuditPageSize
+	}
+
+	var ok bool
+	if filter.Since, ok = parseTime(c, "since"); !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "since must be an RFC 3339 timestamp",
+		})
+	}
+	if filter.Until, ok = parseTime(c, "until"); !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "until must be an RFC 3339 timestamp",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries, hasMore, err := db.GetAuditEntries(ctx, filter)
+	if err != nil {
+		log.Printf("Error getting audit log: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get audit log",
+		})
+	}
+
+	var nextBefore *string
+	if hasMore && len(entries) > 0 {
+		nextBefore = &entries[len(entries)-1].ID
+	}
+
+	return c.JSON(fiber.Map{
+		"entries":     entries,
+		"has_more":    hasMore,
+		"next_before": nextBefore,
+	})
+}
@@ -0,0 +1,9 @@
+package audit
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+func AuditRouter(router fiber.Router) {
+	router.Get("/", GetEntries)
+}
@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"wanshow-bingo/handlers/admin/audit"
+	"wanshow-bingo/handlers/admin/users"
 	"wanshow-bingo/middleware"
 	"wanshow-bingo/utils"
 
@@ -17,4 +19,8 @@ func AdminRouter(router fiber.Router) {
 	hostMiddleware := middleware.RequirePermissionMiddleware("can_host")
 
 	router.Use(adminMiddleware, hostMiddleware)
+
+	// Mounted after the middleware so it runs first
+	users.UsersRouter(router.Group("/users"))
+	audit.AuditRouter(router.Group("/audit"))
 }
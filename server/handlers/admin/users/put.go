@@ -2,6 +2,7 @@ package users
 
 import (
 	"context"
+	"wanshow-bingo/audit"
 	"wanshow-bingo/db"
 
 	"github.com/gofiber/fiber/v2"
@@ -29,6 +30,8 @@ func UpdateUserPermissions(c *fiber.Ctx) error {
 		})
 	}
 
+	before := player.Permissions.GetAllPermissions()
+
 	// Update permissions from the request
 	player.Permissions.SetPermissionsFromMap(req.Permissions)
 
@@ -41,6 +44,8 @@ func UpdateUserPermissions(c *fiber.Ctx) error {
 	}
 
 	permissions := player.Permissions.GetAllPermissions()
+	audit.Record(c, audit.PermissionsUpdate, "player", player.ID, before, permissions)
+
 	return c.JSON(fiber.Map{
 		"user_id":      player.ID,
 		"display_name": player.DisplayName,
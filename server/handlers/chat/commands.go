@@ -0,0 +1,463 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"wanshow-bingo/automod"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/handlers/moderation"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// commandContext is what a slash command gets to work with
+type commandContext struct {
+	Player *models.Player
+	Show   *models.Show
+	Args   []string
+	// Text is everything after the command name, with spacing preserved
+	Text string
+	// Request is the request the command was sent with, for the audit log
+	Request *fiber.Ctx
+}
+
+// commandReply is the result of a slash command. Private replies are only shown to the
+// player who ran the command, public replies are posted to chat as the player's message.
+type commandReply struct {
+	Contents string
+	Public   bool
+}
+
+// command is a slash command players can type into chat
+type command struct {
+	Name        string
+	Usage       string
+	Description string
+	// Permission is required to run the command, zero means anyone who can chat
+	Permission models.Permission
+	// Run executes the command. Errors are shown to the player as they are.
+	Run func(ctx context.Context, cmd *commandContext) (*commandReply, error)
+}
+
+// commands is the registry of slash commands, keyed by name
+var commands = map[string]*command{}
+
+func registerCommand(cmd *command) {
+	commands[cmd.Name] = cmd
+}
+
+func init() {
+	registerCommand(&command{
+		Name:        "help",
+		Usage:       "/help",
+		Description: "List the commands you can use",
+		Run:         runHelp,
+	})
+	registerCommand(&command{
+		Name:        "me",
+		Usage:       "/me <action>",
+		Description: "Describe what you are doing",
+		Run:         runMe,
+	})
+	registerCommand(&command{
+		Name:        "roll",
+		Usage:       "/roll [sides | NdM]",
+		Description: "Roll dice, a six-sided die by default",
+		Run:         runRoll,
+	})
+	registerCommand(&command{
+		Name:        "board",
+		Usage:       "/board",
+		Description: "Show how much of your board has been confirmed",
+		Run:         runBoard,
+	})
+	registerCommand(&command{
+		Name:        "tile",
+		Usage:       "/tile <name>",
+		Description: "Look up a tile and whether it has been confirmed",
+		Run:         runTile,
+	})
+	registerCommand(&command{
+		Name:        "timer",
+		Usage:       "/timer",
+		Description: "List the running timers",
+		Run:         runTimer,
+	})
+}
+
+// isCommand reports whether a message should be handled as a slash command
+func isCommand(contents string) bool {
+	return strings.HasPrefix(contents, "/")
+}
+
+// canRunCommand reports whether a player has the permission a command needs
+func canRunCommand(player *models.Player, cmd *command) bool {
+	return cmd.Permission == 0 || player.Permissions.HasPermission(cmd.Permission)
+}
+
+// runCommand parses and executes a slash command in place of posting a message. Public replies of
+// a shadow muted player are only shown to them, like their messages.
+func runCommand(ctx *fiber.Ctx, player *models.Player, contents string, shadowMute *models.Sanction) error {
+	name, text, _ := strings.Cut(strings.TrimPrefix(contents, "/"), " ")
+	name = strings.ToLower(name)
+	text = strings.TrimSpace(text)
+
+	cmd, ok := commands[name]
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Unknown command /" + name + ", type /help for a list of commands",
+		})
+	}
+
+	if !canRunCommand(player, cmd) {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions to use /" + cmd.Name,
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	latestShow, err := db.GetLatestShow(c)
+	if err != nil {
+		log.Printf("Error getting latest show: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to run command",
+		})
+	}
+
+	reply, err := cmd.Run(c, &commandContext{
+		Player:  player,
+		Show:    latestShow,
+		Args:    strings.Fields(text),
+		Text:    text,
+		Request: ctx,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"usage": cmd.Usage,
+		})
+	}
+
+	response := fiber.Map{
+		"success": true,
+		"command": cmd.Name,
+		"reply":   reply.Contents,
+		"public":  reply.Public,
+	}
+
+	if reply.Public {
+		// Public replies are chat messages, so they follow the show's chat limits like any other
+		if blocked, err := checkShowChatLimits(ctx, player, latestShow.ID, reply.Contents); blocked {
+			return err
+		}
+
+		message, err := postCommandMessage(c, player, latestShow, cmd, reply, shadowMute)
+		if err != nil {
+			log.Printf("Error posting reply to /%s: %s", cmd.Name, err)
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to run command",
+			})
+		}
+		response["message_id"] = message.ID
+
+		// The LLM check runs after publishing, like for messages typed into chat
+		automod.ReviewMessage(message)
+	} else if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.SendEventToPlayers("chat.command", fiber.Map{
+			"command":  cmd.Name,
+			"contents": reply.Contents,
+		}, player.ID)
+	}
+
+	return ctx.JSON(response)
+}
+
+// postCommandMessage saves the public reply of a command as the player's own message, marked
+// with the command so clients can tell it apart from what the player typed, and broadcasts it
+func postCommandMessage(ctx context.Context, player *models.Player, show *models.Show, cmd *command, reply *commandReply, shadowMute *models.Sanction) (*models.Message, error) {
+	message := &models.Message{
+		ShowID:    show.ID,
+		PlayerID:  player.ID,
+		Contents:  reply.Contents,
+		Data:      map[string]interface{}{"command": cmd.Name},
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Shadowed:  shadowMute != nil,
+	}
+	if err := db.PersistMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	event := fiber.Map{
+		"id":         message.ID,
+		"show_id":    message.ShowID,
+		"player_id":  message.PlayerID,
+		"contents":   message.Contents,
+		"system":     message.System,
+		"data":       message.Data,
+		"replying":   message.Replying,
+		"mentions":   message.MentionIDs(),
+		"created_at": message.CreatedAt,
+		"updated_at": message.UpdatedAt,
+		"deleted_at": message.DeletedAt,
+		"player":     player,
+	}
+	if shadowMute != nil {
+		moderation.SendShadowed("chat.message", event, player.ID, shadowMute)
+	} else if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.BroadcastEvent("chat.message", event)
+	}
+
+	return message, nil
+}
+
+func runHelp(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		if canRunCommand(cmd.Player, commands[name]) {
+			lines = append(lines, commands[name].Usage+" - "+commands[name].Description)
+		}
+	}
+
+	return &commandReply{Contents: strings.Join(lines, "\n")}, nil
+}
+
+func runMe(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	if cmd.Text == "" {
+		return nil, errors.New("Tell us what you are doing")
+	}
+	if errMsg := validateContents(cmd.Player.ID, cmd.Text); errMsg != "" {
+		return nil, errors.New(errMsg)
+	}
+
+	return &commandReply{
+		Contents: "*" + cmd.Player.DisplayName + " " + cmd.Text + "*",
+		Public:   true,
+	}, nil
+}
+
+// maxDice and maxDieSides bound what /roll accepts
+const (
+	maxDice     = 10
+	maxDieSides = 1000
+)
+
+// parseDice reads a roll like "20" or "2d6" into a number of dice and their sides
+func parseDice(arg string) (int, int, error) {
+	count, sides := 1, 6
+	if arg != "" {
+		countStr, sidesStr, found := strings.Cut(strings.ToLower(arg), "d")
+		if !found {
+			sidesStr, countStr = countStr, "1"
+		} else if countStr == "" {
+			countStr = "1"
+		}
+
+		var err error
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, fmt.Errorf("Invalid roll %q", arg)
+		}
+		if sides, err = strconv.Atoi(sidesStr); err != nil {
+			return 0, 0, fmt.Errorf("Invalid roll %q", arg)
+		}
+	}
+
+	if count < 1 || count > maxDice {
+		return 0, 0, fmt.Errorf("You can roll between 1 and %d dice", maxDice)
+	}
+	if sides < 2 || sides > maxDieSides {
+		return 0, 0, fmt.Errorf("Dice must have between 2 and %d sides", maxDieSides)
+	}
+
+	return count, sides, nil
+}
+
+func runRoll(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	arg := ""
+	if len(cmd.Args) > 0 {
+		arg = cmd.Args[0]
+	}
+
+	count, sides, err := parseDice(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	rolls := make([]string, 0, count)
+	for range count {
+		roll := rand.Intn(sides) + 1
+		total += roll
+		rolls = append(rolls, strconv.Itoa(roll))
+	}
+
+	contents := fmt.Sprintf("%s rolled %dd%d: **%d**", cmd.Player.DisplayName, count, sides, total)
+	if count > 1 {
+		contents += " (" + strings.Join(rolls, " + ") + ")"
+	}
+
+	return &commandReply{Contents: contents, Public: true}, nil
+}
+
+// confirmedTiles returns the confirmations of a show keyed by tile ID
+func confirmedTiles(ctx context.Context, showID string) (map[string]models.TileConfirmation, error) {
+	confirmations, err := db.GetTileConfirmationsForShow(ctx, showID)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed := make(map[string]models.TileConfirmation, len(confirmations))
+	for _, confirmation := range confirmations {
+		confirmed[confirmation.TileID] = confirmation
+	}
+	return confirmed, nil
+}
+
+func runBoard(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	board, err := db.GetBoardForPlayer(ctx, cmd.Player.ID, cmd.Show.ID)
+	if err != nil {
+		return nil, errors.New("You do not have a board for this show yet")
+	}
+
+	confirmed, err := confirmedTiles(ctx, cmd.Show.ID)
+	if err != nil {
+		log.Printf("Error getting tile confirmations: %s", err)
+		return nil, errors.New("Failed to load your board")
+	}
+
+	done := 0
+	for _, tileID := range board.Tiles {
+		if _, ok := confirmed[tileID]; ok {
+			done++
+		}
+	}
+
+	contents := fmt.Sprintf("%d of %d tiles on your board are confirmed", done, len(board.Tiles))
+	if board.Winner {
+		contents += ", and you have already won"
+	}
+
+	return &commandReply{Contents: contents}, nil
+}
+
+// maxTileMatches is how many tiles /tile lists when a search is ambiguous
+const maxTileMatches = 5
+
+func runTile(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	if cmd.Text == "" {
+		return nil, errors.New("Which tile are you looking for?")
+	}
+
+	tiles, err := db.GetAllTiles(ctx)
+	if err != nil {
+		log.Printf("Error getting tiles: %s", err)
+		return nil, errors.New("Failed to look up tiles")
+	}
+
+	// An exact title wins, otherwise the search must narrow down to one tile
+	search := strings.ToLower(cmd.Text)
+	var matches []models.Tile
+	for _, tile := range tiles {
+		title := strings.ToLower(tile.Title)
+		if title == search {
+			matches = []models.Tile{tile}
+			break
+		}
+		if strings.Contains(title, search) {
+			matches = append(matches, tile)
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("No tile matches %q", cmd.Text)
+	case len(matches) > 1:
+		titles := make([]string, 0, maxTileMatches)
+		for _, tile := range matches[:min(len(matches), maxTileMatches)] {
+			titles = append(titles, tile.Title)
+		}
+		contents := fmt.Sprintf("%d tiles match %q: %s", len(matches), cmd.Text, strings.Join(titles, ", "))
+		return &commandReply{Contents: contents}, nil
+	}
+
+	tile := matches[0]
+	confirmed, err := confirmedTiles(ctx, cmd.Show.ID)
+	if err != nil {
+		log.Printf("Error getting tile confirmations: %s", err)
+		return nil, errors.New("Failed to look up tiles")
+	}
+
+	confirmation, ok := confirmed[tile.ID]
+	if !ok {
+		return &commandReply{Contents: fmt.Sprintf("%q has not been confirmed this show", tile.Title)}, nil
+	}
+
+	contents := fmt.Sprintf("%q was confirmed at %s UTC", tile.Title, confirmation.ConfirmationTime.UTC().Format("15:04"))
+	if confirmation.Context != nil && *confirmation.Context != "" {
+		contents += ": " + *confirmation.Context
+	}
+	return &commandReply{Contents: contents}, nil
+}
+
+// timerStatus describes how far along a running timer is
+func timerStatus(timer *models.Timer, now time.Time) string {
+	switch {
+	case timer.PausedAt != nil && timer.RemainingMs != nil:
+		return "paused, " + formatDuration(time.Duration(*timer.RemainingMs)*time.Millisecond) + " left"
+	case timer.Mode == models.TimerModeStopwatch && timer.StartsAt != nil:
+		return formatDuration(now.Sub(*timer.StartsAt)) + " elapsed"
+	case timer.ExpiresAt != nil:
+		return formatDuration(timer.ExpiresAt.Sub(now)) + " left"
+	default:
+		return "not started"
+	}
+}
+
+// formatDuration renders a duration as m:ss, or h:mm:ss when over an hour
+func formatDuration(d time.Duration) string {
+	d = max(d, 0).Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+func runTimer(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	timers, err := db.GetActiveTimersForShow(ctx, cmd.Show.ID)
+	if err != nil {
+		log.Printf("Error getting active timers: %s", err)
+		return nil, errors.New("Failed to load timers")
+	}
+
+	now := time.Now()
+	lines := make([]string, 0, len(timers))
+	for i := range timers {
+		if !timers[i].VisibleTo(cmd.Player) {
+			continue
+		}
+		lines = append(lines, timers[i].Title+": "+timerStatus(&timers[i], now))
+	}
+
+	if len(lines) == 0 {
+		return &commandReply{Contents: "No timers are running"}, nil
+	}
+	return &commandReply{Contents: strings.Join(lines, "\n")}, nil
+}
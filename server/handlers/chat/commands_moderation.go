@@ -0,0 +1,126 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/handlers/moderation"
+	"wanshow-bingo/sse"
+)
+
+func init() {
+	registerCommand(&command{
+		Name:        "mute",
+		Usage:       "/mute @player [duration] [reason]",
+		Description: "Mute a player in chat, until unmuted unless a duration like 10m is given",
+		Permission:  models.PermCanMuteUsers,
+		Run:         runMute,
+	})
+	registerCommand(&command{
+		Name:        "slow",
+		Usage:       "/slow <seconds | off>",
+		Description: "Set how long players must wait between messages",
+		Permission:  models.PermCanManageChat,
+		Run:         runSlow,
+	})
+}
+
+func runMute(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	if len(cmd.Args) == 0 {
+		return nil, errors.New("Who should be muted?")
+	}
+
+	target, err := db.GetPlayerByIdentifier(ctx, strings.TrimPrefix(cmd.Args[0], "@"))
+	if err != nil {
+		return nil, errors.New("Player not found")
+	}
+	if target.ID == cmd.Player.ID {
+		return nil, errors.New("You cannot mute yourself")
+	}
+
+	sanction := &models.Sanction{
+		PlayerID: target.ID,
+		Type:     models.SanctionMute,
+		Scope:    models.SanctionScopeChat,
+		IssuedBy: &cmd.Player.ID,
+	}
+
+	// The duration is optional, anything after it is the reason
+	rest := cmd.Args[1:]
+	if len(rest) > 0 {
+		if duration, err := time.ParseDuration(rest[0]); err == nil {
+			if duration <= 0 {
+				return nil, errors.New("Mute duration must be positive")
+			}
+			expiresAt := time.Now().Add(duration)
+			sanction.ExpiresAt = &expiresAt
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 0 {
+		reason := strings.Join(rest, " ")
+		sanction.Reason = &reason
+	}
+
+	if err := moderation.IssueSanction(ctx, sanction); err != nil {
+		log.Printf("Error saving sanction: %s", err)
+		return nil, errors.New("Failed to mute player")
+	}
+	audit.Record(cmd.Request, audit.SanctionCreate, "player", target.ID, nil, sanction)
+
+	contents := target.DisplayName + " has been muted"
+	if sanction.ExpiresAt != nil {
+		contents += " until " + sanction.ExpiresAt.UTC().Format("15:04") + " UTC"
+	}
+	return &commandReply{Contents: contents}, nil
+}
+
+func runSlow(ctx context.Context, cmd *commandContext) (*commandReply, error) {
+	if len(cmd.Args) == 0 {
+		return nil, errors.New("How many seconds should players wait between messages?")
+	}
+
+	seconds := 0
+	if cmd.Args[0] != "off" {
+		var err error
+		seconds, err = strconv.Atoi(cmd.Args[0])
+		if err != nil || seconds < 0 {
+			return nil, errors.New("Slow mode must be a number of seconds or off")
+		}
+	}
+
+	settings, err := db.GetChatSettings(ctx, cmd.Show.ID)
+	if err != nil {
+		log.Printf("Error getting chat settings: %s", err)
+		return nil, errors.New("Failed to change slow mode")
+	}
+	before := *settings
+	settings.SlowModeSeconds = seconds
+	settings.UpdatedBy = &cmd.Player.ID
+
+	if err := db.PersistChatSettings(ctx, settings); err != nil {
+		log.Printf("Error saving chat settings: %s", err)
+		return nil, errors.New("Failed to change slow mode")
+	}
+	audit.Record(cmd.Request, audit.ChatSettingsUpdate, "show", settings.ShowID, before, settings)
+
+	chatHub := sse.GetChatHub()
+	if chatHub != nil {
+		chatHub.BroadcastEvent("chat.settings", settings)
+	}
+
+	if seconds == 0 {
+		return &commandReply{Contents: "Slow mode is off", Public: true}, nil
+	}
+	return &commandReply{
+		Contents: fmt.Sprintf("Slow mode is on, players can send a message every %d seconds", seconds),
+		Public:   true,
+	}, nil
+}
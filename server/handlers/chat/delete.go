@@ -0,0 +1,162 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseDeleteReason reads the optional deletion reason from the request body
+func parseDeleteReason(ctx *fiber.Ctx) *string {
+	var req models.DeleteMessageRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			log.Printf("Error parsing delete body: %s", err)
+		}
+	}
+
+	if req.Reason == "" {
+		return nil
+	}
+	return &req.Reason
+}
+
+// DeleteMessage soft deletes a message. Authors may delete their own messages,
+// moderators may delete any message.
+func DeleteMessage(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	messageID := ctx.Params("id")
+	if messageID == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Message ID is required",
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	message, err := db.GetMessageByID(c, messageID)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Message not found",
+		})
+	}
+
+	isAuthor := !message.System && message.PlayerID == player.ID
+	canDelete := player.Permissions.HasPermission(models.PermCanDeleteMessages) ||
+		(isAuthor && player.Permissions.HasPermission(models.PermCanDeleteOwnMessages))
+	if !canDelete {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions to delete this message",
+		})
+	}
+
+	reason := parseDeleteReason(ctx)
+
+	err = db.DeleteMessage(c, messageID, player.ID, reason)
+	if err != nil {
+		log.Printf("Error deleting message %s: %s", messageID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete message",
+		})
+	}
+
+	// Authors removing their own messages are not acting as moderators
+	if !isAuthor {
+		audit.Record(ctx, audit.MessageDelete, "message", message.ID, message, fiber.Map{"reason": reason})
+	}
+
+	chatHub := sse.GetChatHub()
+	if chatHub != nil {
+		chatHub.BroadcastEvent("chat.message.deleted", fiber.Map{
+			"id":         message.ID,
+			"show_id":    message.ShowID,
+			"deleted_by": player.ID,
+			"reason":     reason,
+		})
+	}
+
+	return ctx.JSON(fiber.Map{
+		"success":    true,
+		"message_id": message.ID,
+	})
+}
+
+// PurgePlayerMessages deletes every message a player sent during the current show
+func PurgePlayerMessages(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !player.Permissions.HasPermission(models.PermCanDeleteMessages) {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions to delete messages",
+		})
+	}
+
+	targetID := ctx.Params("id")
+	if targetID == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Player ID is required",
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	latestShow, err := db.GetLatestShow(c)
+	if err != nil {
+		log.Printf("Error getting latest show: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete messages",
+		})
+	}
+
+	reason := parseDeleteReason(ctx)
+
+	messageIDs, err := db.PurgePlayerMessages(c, latestShow.ID, targetID, player.ID, reason)
+	if err != nil {
+		log.Printf("Error purging messages of player %s: %s", targetID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete messages",
+		})
+	}
+
+	audit.Record(ctx, audit.MessagePurge, "player", targetID, nil, fiber.Map{
+		"show_id":     latestShow.ID,
+		"message_ids": messageIDs,
+		"reason":      reason,
+	})
+
+	chatHub := sse.GetChatHub()
+	if chatHub != nil && len(messageIDs) > 0 {
+		chatHub.BroadcastEvent("chat.messages.purged", fiber.Map{
+			"player_id":   targetID,
+			"show_id":     latestShow.ID,
+			"message_ids": messageIDs,
+			"deleted_by":  player.ID,
+			"reason":      reason,
+		})
+	}
+
+	return ctx.JSON(fiber.Map{
+		"success":     true,
+		"message_ids": messageIDs,
+	})
+}
@@ -0,0 +1,144 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/automod"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/handlers/moderation"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// messageEditWindow is how long after sending a message its author may edit it
+const messageEditWindow = 5 * time.Minute
+
+// EditMessage replaces the contents of the player's own message
+func EditMessage(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	messageID := ctx.Params("id")
+	if messageID == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Message ID is required",
+		})
+	}
+
+	if blocked, err := checkChatSanction(ctx, player.ID); blocked {
+		return err
+	}
+
+	var msgBody models.MessageRequest
+	if err := ctx.BodyParser(&msgBody); err != nil {
+		log.Printf("Error parsing message body: %s", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	message, err := db.GetMessageByID(c, messageID)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Message not found",
+		})
+	}
+
+	if message.System || message.PlayerID != player.ID {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only edit your own messages",
+		})
+	}
+
+	// Command output, such as a dice roll, would no longer be what the command produced
+	if message.Command() != "" {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Messages posted by commands cannot be edited",
+		})
+	}
+
+	if time.Since(message.CreatedAt) > messageEditWindow {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Message can no longer be edited",
+		})
+	}
+
+	// Edits go through the same checks as new messages
+	if errMsg := validateContents(player.ID, msgBody.Contents); errMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": errMsg,
+		})
+	}
+
+	// Edits made while shadow muted are only seen by the author from then on
+	shadowMute := getShadowMute(player.ID)
+
+	updated, err := db.EditMessage(c, messageID, msgBody.Contents, player.ID, shadowMute != nil)
+	if err != nil {
+		log.Printf("Error editing message %s: %s", messageID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to edit message",
+		})
+	}
+
+	edit := fiber.Map{
+		"id":        updated.ID,
+		"show_id":   updated.ShowID,
+		"contents":  updated.Contents,
+		"edited_at": updated.EditedAt,
+	}
+	if updated.Shadowed {
+		moderation.SendShadowed("chat.message.updated", edit, player.ID, shadowMute)
+	} else if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.BroadcastEvent("chat.message.updated", edit)
+	}
+
+	automod.ReviewMessage(updated)
+
+	return ctx.JSON(updated)
+}
+
+// GetMessageRevisions returns the previous contents of an edited message
+func GetMessageRevisions(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !player.Permissions.HasPermission(models.PermCanModerate) {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions to view message history",
+		})
+	}
+
+	messageID := ctx.Params("id")
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	revisions, err := db.GetMessageRevisions(c, messageID)
+	if err != nil {
+		log.Printf("Error getting revisions for message %s: %s", messageID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get message history",
+		})
+	}
+
+	return ctx.JSON(fiber.Map{
+		"message_id": messageID,
+		"revisions":  revisions,
+	})
+}
@@ -0,0 +1,108 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"math"
+	"strconv"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// messageLimiter limits how fast each player can send messages and whispers.
+// Players may burst CHAT_RATE_LIMIT_BURST messages, refilling at CHAT_RATE_LIMIT_PER_MINUTE.
+var messageLimiter = utils.NewRateLimiter(
+	utils.GetEnvInt("CHAT_RATE_LIMIT_BURST", 5),
+	utils.GetEnvInt("CHAT_RATE_LIMIT_PER_MINUTE", 20),
+)
+
+// duplicateWindow is how long the same message cannot be posted again by the same player
+var duplicateWindow = time.Duration(utils.GetEnvInt("CHAT_DUPLICATE_WINDOW_SECONDS", 30)) * time.Second
+
+// isChatLimitExempt reports whether a player bypasses rate limits and chat restrictions
+func isChatLimitExempt(player *models.Player) bool {
+	return player.Permissions.HasPermission(models.PermCanHost) ||
+		player.Permissions.HasPermission(models.PermCanModerate)
+}
+
+// rateLimited responds with 429 and tells the client how long to wait
+func rateLimited(ctx *fiber.Ctx, message string, retryAfter time.Duration) (bool, error) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return true, ctx.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error":       message,
+		"retry_after": seconds,
+	})
+}
+
+// checkRateLimit takes a token from the player's bucket, responding with 429 and returning
+// true if it is empty
+func checkRateLimit(ctx *fiber.Ctx, player *models.Player) (bool, error) {
+	if isChatLimitExempt(player) {
+		return false, nil
+	}
+
+	if ok, retryAfter := messageLimiter.Allow(player.ID); !ok {
+		return rateLimited(ctx, "You are sending messages too quickly", retryAfter)
+	}
+	return false, nil
+}
+
+// checkShowChatLimits enforces the show's account age requirement and slow mode, and
+// suppresses duplicate messages. It responds and returns true if the message may not be posted.
+func checkShowChatLimits(ctx *fiber.Ctx, player *models.Player, showID string, contents string) (bool, error) {
+	if isChatLimitExempt(player) {
+		return false, nil
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	settings, err := db.GetChatSettings(c, showID)
+	if err != nil {
+		log.Printf("Error getting chat settings for show %s: %s", showID, err)
+		return false, nil
+	}
+
+	// New accounts must wait before chatting, to slow down raids
+	if settings.MinAccountAgeMinutes > 0 {
+		allowedAt := player.CreatedAt.Add(time.Duration(settings.MinAccountAgeMinutes) * time.Minute)
+		if wait := time.Until(allowedAt); wait > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			return true, ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":       "Your account is too new to chat during this show",
+				"retry_after": int(math.Ceil(wait.Seconds())),
+			})
+		}
+	}
+
+	if settings.SlowModeSeconds > 0 {
+		last, err := db.GetLastMessageTime(c, showID, player.ID)
+		if err != nil {
+			log.Printf("Error getting last message time for %s: %s", player.ID, err)
+		} else if last != nil {
+			if wait := time.Until(last.Add(time.Duration(settings.SlowModeSeconds) * time.Second)); wait > 0 {
+				return rateLimited(ctx, "Slow mode is on", wait)
+			}
+		}
+	}
+
+	if duplicateWindow > 0 {
+		last, err := db.GetRecentDuplicateTime(c, showID, player.ID, contents, time.Now().Add(-duplicateWindow))
+		if err != nil {
+			log.Printf("Error checking duplicate messages for %s: %s", player.ID, err)
+		} else if last != nil {
+			return rateLimited(ctx, "You already sent that message", time.Until(last.Add(duplicateWindow)))
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/sse"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxMentions is the most players a single message may mention
+var maxMentions = utils.GetEnvInt("CHAT_MAX_MENTIONS", 5)
+
+// resolveMentions looks up the players mentioned in a message, leaving out the author.
+// It returns an error message for the player if the message mentions too many people.
+func resolveMentions(author *models.Player, contents string) ([]models.Player, string) {
+	names := utils.ParseMentions(contents)
+	if len(names) == 0 {
+		return nil, ""
+	}
+	if len(names) > maxMentions {
+		return nil, fmt.Sprintf("Too many mentions (max %d)", maxMentions)
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	players, err := db.GetPlayersByDisplayNames(c, names)
+	if err != nil {
+		log.Printf("Error resolving mentions: %s", err)
+		return nil, ""
+	}
+
+	mentioned := make([]models.Player, 0, len(players))
+	for _, player := range players {
+		if player.ID != author.ID {
+			mentioned = append(mentioned, player)
+		}
+	}
+	return mentioned, ""
+}
+
+// notifyMentions sends a chat.mention event to each mentioned player who has not blocked the author
+func notifyMentions(message *models.Message, author *models.Player, mentioned []models.Player) {
+	chatHub := sse.GetChatHub()
+	if chatHub == nil {
+		return
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	for _, player := range mentioned {
+		blocked, err := db.IsBlockedBetween(c, author.ID, player.ID)
+		if err != nil || blocked {
+			continue
+		}
+
+		chatHub.SendEventToPlayers("chat.mention", fiber.Map{
+			"message_id": message.ID,
+			"show_id":    message.ShowID,
+			"contents":   message.Contents,
+			"from": fiber.Map{
+				"id":           author.ID,
+				"display_name": author.DisplayName,
+			},
+		}, player.ID)
+	}
+}
@@ -0,0 +1,164 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/avatar"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultMessagePageSize = 50
+	maxMessagePageSize     = 200
+)
+
+// avatarKey safely extracts string value from *string
+func avatarKey(avatar *string) string {
+	if avatar == nil {
+		return ""
+	}
+	return *avatar
+}
+
+// GetMessages pages through the chat of a show using (created_at, id) cursors
+func GetMessages(ctx *fiber.Ctx) error {
+	limit := ctx.QueryInt("limit", defaultMessagePageSize)
+	if limit < 1 || limit > maxMessagePageSize {
+		limit = defaultMessagePageSize
+	}
+
+	var before, after *models.MessageCursor
+	if value := ctx.Query("before"); value != "" {
+		cursor, err := models.DecodeMessageCursor(value)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid before cursor",
+			})
+		}
+		before = &cursor
+	}
+	if value := ctx.Query("after"); value != "" {
+		cursor, err := models.DecodeMessageCursor(value)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid after cursor",
+			})
+		}
+		after = &cursor
+	}
+	if before != nil && after != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Only one of before and after may be given",
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	showID := ctx.Query("show_id")
+	if showID == "" {
+		latestShow, err := db.GetLatestShow(c)
+		if err != nil {
+			log.Printf("Error getting latest show: %s", err)
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to get messages",
+			})
+		}
+		showID = latestShow.ID
+	}
+
+	// Shadowed messages and reply previews are only included for their author
+	viewer := viewerID(ctx)
+	messages, hasMore, err := db.GetMessagesPage(c, showID, viewer, before, after, limit)
+	if err != nil {
+		log.Printf("Error getting messages for show %s: %s", showID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get messages",
+		})
+	}
+
+	// Load the authors and the parents of replies in bulk
+	messageMap := make(map[string]*models.Message, len(messages))
+	playerIDs := make([]string, 0, len(messages))
+	messageIDs := make([]string, 0, len(messages))
+	for i := range messages {
+		messageMap[messages[i].ID] = &messages[i]
+		playerIDs = append(playerIDs, messages[i].PlayerID)
+		messageIDs = append(messageIDs, messages[i].ID)
+	}
+
+	players, err := db.GetPlayersByIDs(c, playerIDs)
+	if err != nil {
+		log.Printf("Error getting message authors: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get messages",
+		})
+	}
+
+	reactions, err := db.GetReactionCounts(c, messageIDs, viewer)
+	if err != nil {
+		log.Printf("Error getting message reactions: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get messages",
+		})
+	}
+
+	result := make([]fiber.Map, 0, len(messages))
+	for _, msg := range messages {
+		messageWithPlayer := fiber.Map{
+			"id":         msg.ID,
+			"show_id":    msg.ShowID,
+			"player_id":  msg.PlayerID,
+			"contents":   msg.Contents,
+			"system":     msg.System,
+			"replying":   msg.Replying,
+			"mentions":   msg.MentionIDs(),
+			"created_at": msg.CreatedAt,
+			"updated_at": msg.UpdatedAt,
+			"edited_at":  msg.EditedAt,
+			"reactions":  reactions[msg.ID],
+			"player":     nil,
+		}
+		if msg.Kind != nil {
+			messageWithPlayer["kind"] = msg.Kind
+			messageWithPlayer["data"] = msg.Data
+		}
+		if player, ok := players[msg.PlayerID]; ok {
+			messageWithPlayer["player"] = fiber.Map{
+				"id":           player.ID,
+				"display_name": player.DisplayName,
+				"avatar":       avatar.GetAvatarURL(avatarKey(player.Avatar)),
+				"permissions":  player.Permissions,
+				"settings":     player.Settings,
+				"created_at":   player.CreatedAt,
+			}
+		}
+		if msg.Replying != nil {
+			parent, ok := messageMap[*msg.Replying]
+			if !ok {
+				parent, _ = db.GetMessageByID(c, *msg.Replying)
+			}
+			if parent != nil && parent.VisibleTo(viewer) {
+				messageWithPlayer["parent"] = parent.Preview()
+			}
+		}
+		result = append(result, messageWithPlayer)
+	}
+
+	cursors := fiber.Map{"before": nil, "after": nil}
+	if len(messages) > 0 {
+		cursors["before"] = messages[0].Cursor().Encode()
+		cursors["after"] = messages[len(messages)-1].Cursor().Encode()
+	}
+
+	return ctx.JSON(fiber.Map{
+		"show_id":  showID,
+		"messages": result,
+		"cursors":  cursors,
+		"has_more": hasMore,
+	})
+}
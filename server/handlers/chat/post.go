@@ -4,16 +4,70 @@ import (
 	"context"
 	"log"
 	"time"
+	"wanshow-bingo/automod"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
+	"wanshow-bingo/handlers/moderation"
 	"wanshow-bingo/middleware"
 	"wanshow-bingo/sse"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
-	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
+// validateContents checks the length of a message and runs it through moderation,
+// returning the error to show the player or an empty string if it is allowed. Messages
+// moderation rejects count as a strike against the player.
+func validateContents(playerID string, contents string) string {
+	if len(contents) == 0 {
+		return "Message content cannot be empty"
+	}
+
+	if len(contents) > 500 {
+		return "Message too long (max 500 characters)"
+	}
+
+	moderationResult := utils.ModerateContent(contents)
+	if !moderationResult.Allowed {
+		log.Printf("Message rejected for user %s by %s (%s): %s", playerID, moderationResult.Stage, moderationResult.RuleID, moderationResult.Reason)
+		blockMessage(playerID, moderationResult)
+		return moderationResult.Reason
+	}
+
+	return ""
+}
+
+// checkChatSanction responds with 403 and returns true if the player is muted, kicked or
+// banned from chat
+func checkChatSanction(ctx *fiber.Ctx, playerID string) (bool, error) {
+	sanction, err := db.GetActiveSanction(context.Background(), playerID, models.SanctionScopeChat,
+		models.SanctionMute, models.SanctionKick, models.SanctionBan)
+	if err != nil {
+		log.Printf("Error checking sanctions for %s: %s", playerID, err)
+		return false, nil
+	}
+	if sanction == nil {
+		return false, nil
+	}
+
+	return true, ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error":    "You cannot send messages while " + sanctionState(sanction.Type),
+		"sanction": sanction,
+	})
+}
+
+// sanctionState describes a sanction type for error messages
+func sanctionState(t models.SanctionType) string {
+	switch t {
+	case models.SanctionMute:
+		return "muted"
+	case models.SanctionKick:
+		return "kicked"
+	default:
+		return "banned"
+	}
+}
+
 func Post(ctx *fiber.Ctx) error {
 	// Get authenticated player
 	player, err := middleware.GetPlayerFromContext(ctx)
@@ -30,6 +84,16 @@ func Post(ctx *fiber.Ctx) error {
 		})
 	}
 
+	if blocked, err := checkChatSanction(ctx, player.ID); blocked {
+		return err
+	}
+
+	if blocked, err := checkRateLimit(ctx, player); blocked {
+		return err
+	}
+
+	shadowMute := getShadowMute(player.ID)
+
 	// Parse request body
 	var msgBody models.MessageRequest
 	if err := ctx.BodyParser(&msgBody); err != nil {
@@ -39,41 +103,52 @@ func Post(ctx *fiber.Ctx) error {
 		})
 	}
 
-	// Validate message content
-	if len(msgBody.Contents) == 0 {
+	// Messages starting with a slash are commands and are never stored as they are
+	if isCommand(msgBody.Contents) {
+		return runCommand(ctx, player, msgBody.Contents, shadowMute)
+	}
+
+	// Validate and moderate message content
+	if errMsg := validateContents(player.ID, msgBody.Contents); errMsg != "" {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Message content cannot be empty",
+			"error": errMsg,
 		})
 	}
 
-	if len(msgBody.Contents) > 500 {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Message too long (max 500 characters)",
+	// Messages always belong to the latest show
+	latestShow, err := db.GetLatestShow(context.Background())
+	if err != nil {
+		log.Printf("Error getting latest show: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save message",
 		})
 	}
 
-	// Moderate content
-	moderationResult := utils.ModerateContent(msgBody.Contents)
-	if !moderationResult.Allowed {
-		log.Printf("Message rejected for user %s: %s", player.ID, moderationResult.Reason)
+	if blocked, err := checkShowChatLimits(ctx, player, latestShow.ID, msgBody.Contents); blocked {
+		return err
+	}
+
+	mentioned, errMsg := resolveMentions(player, msgBody.Contents)
+	if errMsg != "" {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Message contains inappropriate content",
+			"error": errMsg,
 		})
 	}
 
-	// Generate message ID
-	messageID, err := gonanoid.New(10)
-	if err != nil {
-		log.Printf("Error generating message ID: %s", err)
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate message ID",
-		})
+	// Replies must point at a message from the same show
+	var parent *models.Message
+	if msgBody.Replying != nil && *msgBody.Replying != "" {
+		parent, err = db.GetMessageByID(context.Background(), *msgBody.Replying)
+		if err != nil || parent.ShowID != latestShow.ID || !parent.VisibleTo(player.ID) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Message being replied to does not exist",
+			})
+		}
 	}
 
-	// Create message object
+	// Create message object, the ID is generated when it is persisted
 	message := &models.Message{
-		ID:        messageID,
-		ShowID:    "", // Will be set by SaveMessage to latest show
+		ShowID:    latestShow.ID,
 		PlayerID:  player.ID,
 		Contents:  msgBody.Contents,
 		System:    false,
@@ -81,10 +156,17 @@ func Post(ctx *fiber.Ctx) error {
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 		DeletedAt: nil,
+		Shadowed:  shadowMute != nil,
+	}
+	if parent != nil {
+		message.Replying = &parent.ID
+	}
+	for _, mentionedPlayer := range mentioned {
+		message.Mentions = append(message.Mentions, mentionedPlayer.ID)
 	}
 
 	// Save message to database
-	err = db.SaveMessage(context.Background(), message)
+	err = db.PersistMessage(context.Background(), message)
 	if err != nil {
 		log.Printf("Error saving message: %s", err)
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -92,30 +174,42 @@ func Post(ctx *fiber.Ctx) error {
 		})
 	}
 
-	// Broadcast message to chat hub
-	chatHub := sse.GetChatHub()
-	if chatHub != nil {
-		// Create message with player data for frontend
-		messageWithPlayer := fiber.Map{
-			"id":         message.ID,
-			"show_id":    message.ShowID,
-			"player_id":  message.PlayerID,
-			"contents":   message.Contents,
-			"system":     message.System,
-			"replying":   message.Replying,
-			"created_at": message.CreatedAt,
-			"updated_at": message.UpdatedAt,
-			"deleted_at": message.DeletedAt,
-			"player":     player,
-		}
-		chatHub.BroadcastEvent("chat.message", messageWithPlayer)
+	// Create message with player data for frontend
+	messageWithPlayer := fiber.Map{
+		"id":         message.ID,
+		"show_id":    message.ShowID,
+		"player_id":  message.PlayerID,
+		"contents":   message.Contents,
+		"system":     message.System,
+		"replying":   message.Replying,
+		"mentions":   message.MentionIDs(),
+		"created_at": message.CreatedAt,
+		"updated_at": message.UpdatedAt,
+		"deleted_at": message.DeletedAt,
+		"player":     player,
+	}
+	if parent != nil {
+		messageWithPlayer["parent"] = parent.Preview()
+	}
+
+	// Shadow muted players see their message go through, nobody else in chat does
+	if shadowMute != nil {
+		moderation.SendShadowed("chat.message", messageWithPlayer, player.ID, shadowMute)
 	} else {
-		log.Printf("Warning: Chat hub not available for broadcasting")
+		if chatHub := sse.GetChatHub(); chatHub != nil {
+			chatHub.BroadcastEvent("chat.message", messageWithPlayer)
+		} else {
+			log.Printf("Warning: Chat hub not available for broadcasting")
+		}
+		notifyMentions(message, player, mentioned)
 	}
 
+	// The LLM check runs after publishing, flagged messages are removed afterwards
+	automod.ReviewMessage(message)
+
 	// Return success response
 	return ctx.JSON(fiber.Map{
 		"success":    true,
-		"message_id": messageID,
+		"message_id": message.ID,
 	})
 }
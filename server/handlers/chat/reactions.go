@@ -0,0 +1,129 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseReaction authenticates the player and validates the message and emoji of a reaction
+// request. It returns a response when the request cannot go ahead.
+func parseReaction(ctx *fiber.Ctx) (*models.Player, *models.Message, string, error) {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return nil, nil, "", ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !player.Permissions.HasPermission(models.PermCanChat) {
+		return nil, nil, "", ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions to react to messages",
+		})
+	}
+
+	if blocked, err := checkChatSanction(ctx, player.ID); blocked {
+		return nil, nil, "", err
+	}
+
+	var req models.ReactionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return nil, nil, "", ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if !models.IsReactionEmoji(req.Emoji) {
+		return nil, nil, "", ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Unsupported reaction",
+			"allowed": models.ReactionEmojis,
+		})
+	}
+
+	message, err := db.GetMessageByID(context.Background(), ctx.Params("id"))
+	if err != nil || !message.VisibleTo(player.ID) {
+		return nil, nil, "", ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Message not found",
+		})
+	}
+
+	return player, message, req.Emoji, nil
+}
+
+// AddReaction reacts to a message with one of the allowed emoji
+func AddReaction(ctx *fiber.Ctx) error {
+	player, message, emoji, errResp := parseReaction(ctx)
+	if player == nil {
+		return errResp
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Reactions added while shadow muted only ever count for the player themselves
+	shadowed := getShadowMute(player.ID) != nil
+
+	added, err := db.AddReaction(c, message.ID, player.ID, emoji, shadowed)
+	if err != nil {
+		log.Printf("Error adding reaction to %s: %s", message.ID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to add reaction",
+		})
+	}
+
+	if added {
+		sendReactionDelta(player.ID, message.ID, emoji, 1, shadowed)
+	}
+
+	return ctx.JSON(fiber.Map{
+		"success": true,
+	})
+}
+
+// RemoveReaction takes back the player's reaction to a message
+func RemoveReaction(ctx *fiber.Ctx) error {
+	player, message, emoji, errResp := parseReaction(ctx)
+	if player == nil {
+		return errResp
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	removed, shadowed, err := db.RemoveReaction(c, message.ID, player.ID, emoji)
+	if err != nil {
+		log.Printf("Error removing reaction from %s: %s", message.ID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to remove reaction",
+		})
+	}
+
+	if removed {
+		sendReactionDelta(player.ID, message.ID, emoji, -1, shadowed)
+	}
+
+	return ctx.JSON(fiber.Map{
+		"success": true,
+	})
+}
+
+// sendReactionDelta queues a reaction change for the coalesced broadcast, or for shadowed
+// reactions sends it straight to the player who made it
+func sendReactionDelta(playerID string, messageID string, emoji string, delta int, shadowed bool) {
+	if !shadowed {
+		sse.QueueReactionDelta(messageID, emoji, delta)
+		return
+	}
+
+	if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.SendEventToPlayers("chat.reaction", fiber.Map{
+			"reactions": []sse.ReactionDelta{{MessageID: messageID, Emoji: emoji, Delta: delta}},
+		}, playerID)
+	}
+}
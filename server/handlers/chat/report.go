@@ -0,0 +1,40 @@
+package chat
+
+import (
+	"context"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/handlers/moderation"
+	"wanshow-bingo/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReportMessage reports a chat message to the moderators
+func ReportMessage(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	message, err := db.GetMessageByID(context.Background(), ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Message not found",
+		})
+	}
+
+	if message.System {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "System messages cannot be reported",
+		})
+	}
+
+	return moderation.FileReport(ctx, &models.Report{
+		ReporterID: player.ID,
+		PlayerID:   message.PlayerID,
+		MessageID:  &message.ID,
+	}, message)
+}
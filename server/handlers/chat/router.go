@@ -15,4 +15,17 @@ func init() {
 func ChatRouter(router fiber.Router) {
 	router.Post("/", middleware.AuthMiddleware, Post)
 	router.Post("/s", PostSystem)
+	router.Get("/settings", GetSettings)
+	router.Put("/settings", middleware.AuthMiddleware, PutSettings)
+	router.Get("/whispers", middleware.AuthMiddleware, GetWhispers)
+	router.Post("/whispers", middleware.AuthMiddleware, PostWhisper)
+	router.Get("/messages", middleware.OptionalPlayerAuthMiddleware, GetMessages)
+	router.Get("/messages/:id/thread", middleware.OptionalPlayerAuthMiddleware, GetThread)
+	router.Put("/messages/:id", middleware.AuthMiddleware, EditMessage)
+	router.Delete("/messages/:id", middleware.AuthMiddleware, DeleteMessage)
+	router.Get("/messages/:id/revisions", middleware.AuthMiddleware, GetMessageRevisions)
+	router.Post("/messages/:id/reactions", middleware.AuthMiddleware, AddReaction)
+	router.Delete("/messages/:id/reactions", middleware.AuthMiddleware, RemoveReaction)
+	router.Post("/messages/:id/report", middleware.AuthMiddleware, ReportMessage)
+	router.Delete("/players/:id/messages", middleware.AuthMiddleware, PurgePlayerMessages)
 }
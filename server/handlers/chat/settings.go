@@ -0,0 +1,107 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSettings returns the chat settings of the latest show
+func GetSettings(ctx *fiber.Ctx) error {
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	latestShow, err := db.GetLatestShow(c)
+	if err != nil {
+		log.Printf("Error getting latest show: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get chat settings",
+		})
+	}
+
+	settings, err := db.GetChatSettings(c, latestShow.ID)
+	if err != nil {
+		log.Printf("Error getting chat settings: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get chat settings",
+		})
+	}
+
+	return ctx.JSON(settings)
+}
+
+// PutSettings changes the slow mode and account age requirement of a show, the latest
+// show unless show_id is given
+func PutSettings(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !player.Permissions.HasPermission(models.PermCanHost) && !player.Permissions.HasPermission(models.PermCanManageChat) {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions to change chat settings",
+		})
+	}
+
+	var settings models.ChatSettings
+	if err := ctx.BodyParser(&settings); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if settings.SlowModeSeconds < 0 || settings.MinAccountAgeMinutes < 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Chat settings cannot be negative",
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if settings.ShowID == "" {
+		latestShow, err := db.GetLatestShow(c)
+		if err != nil {
+			log.Printf("Error getting latest show: %s", err)
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to save chat settings",
+			})
+		}
+		settings.ShowID = latestShow.ID
+	}
+	settings.UpdatedBy = &player.ID
+
+	before, err := db.GetChatSettings(c, settings.ShowID)
+	if err != nil {
+		log.Printf("Error getting chat settings: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save chat settings",
+		})
+	}
+
+	if err := db.PersistChatSettings(c, &settings); err != nil {
+		log.Printf("Error saving chat settings: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save chat settings",
+		})
+	}
+
+	audit.Record(ctx, audit.ChatSettingsUpdate, "show", settings.ShowID, before, settings)
+
+	chatHub := sse.GetChatHub()
+	if chatHub != nil {
+		chatHub.BroadcastEvent("chat.settings", settings)
+	}
+
+	return ctx.JSON(settings)
+}
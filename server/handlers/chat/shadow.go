@@ -0,0 +1,31 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getShadowMute returns the player's active shadow mute, or nil if they are not shadow muted.
+// Errors are logged and treated as not muted, like the other sanction checks.
+func getShadowMute(playerID string) *models.Sanction {
+	sanction, err := db.GetActiveSanction(context.Background(), playerID, models.SanctionScopeChat, models.SanctionShadowMute)
+	if err != nil {
+		log.Printf("Error checking shadow mute for %s: %s", playerID, err)
+		return nil
+	}
+	return sanction
+}
+
+// viewerID returns the ID of the player making a request that may be anonymous, so their own
+// shadowed messages can be included
+func viewerID(ctx *fiber.Ctx) string {
+	if player, err := middleware.GetPlayerFromContext(ctx); err == nil && player != nil {
+		return player.ID
+	}
+	return ""
+}
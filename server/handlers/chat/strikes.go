@@ -0,0 +1,83 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/automod"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/handlers/moderation"
+	"wanshow-bingo/sse"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// blockMessage records a strike against a player whose message moderation rejected, escalates
+// to a mute or a moderator review once enough strikes add up, and tells the player privately
+// why the message was blocked
+func blockMessage(playerID string, result *utils.ModerationResult) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	notice := fiber.Map{
+		"reason":  result.Reason,
+		"rule_id": result.RuleID,
+	}
+
+	outcome, err := automod.RecordStrike(ctx, playerID, result)
+	if err != nil {
+		log.Printf("Error recording strike for %s: %s", playerID, err)
+	} else if outcome != nil {
+		notice["strikes"] = len(outcome.Active)
+		notice["strike_decay_minutes"] = int(automod.StrikeDecay.Minutes())
+		if sanction := escalate(ctx, playerID, outcome); sanction != nil {
+			notice["sanction"] = sanction
+		}
+	}
+
+	if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.SendEventToPlayers("chat.blocked", notice, playerID)
+	}
+}
+
+// escalate applies the escalation step a strike reached, returning the mute it issued if any
+func escalate(ctx context.Context, playerID string, outcome *automod.StrikeOutcome) *models.Sanction {
+	if outcome.Step == nil {
+		return nil
+	}
+
+	summary := outcome.Summary()
+	switch outcome.Step.Action {
+	case automod.EscalateMute:
+		expiresAt := time.Now().Add(outcome.Step.Duration)
+		sanction := &models.Sanction{
+			PlayerID:  playerID,
+			Type:      models.SanctionMute,
+			Scope:     models.SanctionScopeChat,
+			Reason:    &summary,
+			ExpiresAt: &expiresAt,
+		}
+		if err := moderation.IssueSanction(ctx, sanction); err != nil {
+			log.Printf("Error muting %s after strikes: %s", playerID, err)
+			return nil
+		}
+		audit.RecordSystem(audit.SanctionCreate, "player", playerID, nil, sanction)
+		log.Printf("Muted %s for %s after %s", playerID, outcome.Step.Duration, summary)
+		return sanction
+	case automod.EscalateReview:
+		report := &models.Report{
+			ReporterID: audit.SystemActor,
+			PlayerID:   playerID,
+			Category:   models.ReportOther,
+			Details:    &summary,
+		}
+		if _, err := db.PersistReport(ctx, report); err != nil {
+			log.Printf("Error flagging %s for review after strikes: %s", playerID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/db"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetThread returns the conversation a message is part of, oldest message first
+func GetThread(ctx *fiber.Ctx) error {
+	messageID := ctx.Params("id")
+	if messageID == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Message ID is required",
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	messages, err := db.GetMessageThread(c, messageID, viewerID(ctx))
+	if err != nil {
+		log.Printf("Error getting thread for message %s: %s", messageID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get thread",
+		})
+	}
+
+	if len(messages) == 0 {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Message not found",
+		})
+	}
+
+	return ctx.JSON(fiber.Map{
+		"message_id": messageID,
+		"messages":   messages,
+	})
+}
@@ -0,0 +1,175 @@
+package chat
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/automod"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// whisperHistoryLimit is the number of recent whispers returned by GetWhispers
+const whisperHistoryLimit = 50
+
+// PostWhisper sends a private message to another player, delivered only to the
+// sender and recipient
+func PostWhisper(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !player.Permissions.HasPermission(models.PermCanSendWhispers) {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions to send whispers",
+		})
+	}
+
+	if blocked, err := checkChatSanction(ctx, player.ID); blocked {
+		return err
+	}
+
+	if blocked, err := checkRateLimit(ctx, player); blocked {
+		return err
+	}
+
+	var body models.WhisperRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		log.Printf("Error parsing whisper body: %s", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.Recipient == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Recipient is required",
+		})
+	}
+
+	if errMsg := validateContents(player.ID, body.Contents); errMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": errMsg,
+		})
+	}
+
+	// Whispers cannot be removed from the recipient's stream later, so the LLM check runs first
+	if result := automod.CheckBeforeDelivery(body.Contents); !result.Allowed {
+		log.Printf("Whisper rejected for user %s by %s (%s): %s", player.ID, result.Stage, result.RuleID, result.Reason)
+		blockMessage(player.ID, result)
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": result.Reason,
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	recipient, err := db.GetPlayerByIdentifier(c, body.Recipient)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Player not found",
+		})
+	}
+
+	if recipient.ID == player.ID {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot whisper yourself",
+		})
+	}
+
+	// Blocks in either direction look the same as whispers being turned off,
+	// so players cannot tell they have been blocked
+	blocked, err := db.IsBlockedBetween(c, player.ID, recipient.ID)
+	if err != nil {
+		log.Printf("Error checking blocks between %s and %s: %s", player.ID, recipient.ID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to send whisper",
+		})
+	}
+	if blocked || !recipient.AllowsWhispers() {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "This player is not accepting whispers",
+		})
+	}
+
+	// Whispers sent while shadow muted look delivered to the sender but never arrive
+	whisper := &models.Whisper{
+		SenderID:    player.ID,
+		RecipientID: recipient.ID,
+		Contents:    body.Contents,
+		Shadowed:    getShadowMute(player.ID) != nil,
+	}
+	if latestShow, err := db.GetLatestShow(c); err == nil {
+		whisper.ShowID = &latestShow.ID
+	}
+
+	if err := db.PersistWhisper(c, whisper); err != nil {
+		log.Printf("Error saving whisper: %s", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to send whisper",
+		})
+	}
+
+	audience := []string{player.ID}
+	if !whisper.Shadowed {
+		audience = append(audience, recipient.ID)
+	}
+
+	chatHub := sse.GetChatHub()
+	if chatHub != nil {
+		chatHub.SendEventToPlayers("chat.whisper", fiber.Map{
+			"id":           whisper.ID,
+			"show_id":      whisper.ShowID,
+			"sender_id":    whisper.SenderID,
+			"recipient_id": whisper.RecipientID,
+			"contents":     whisper.Contents,
+			"created_at":   whisper.CreatedAt,
+			"sender": fiber.Map{
+				"id":           player.ID,
+				"display_name": player.DisplayName,
+			},
+			"recipient": fiber.Map{
+				"id":           recipient.ID,
+				"display_name": recipient.DisplayName,
+			},
+		}, audience...)
+	}
+
+	return ctx.JSON(fiber.Map{
+		"success": true,
+		"whisper": whisper,
+	})
+}
+
+// GetWhispers returns the recent whispers the player sent or received
+func GetWhispers(ctx *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(ctx)
+	if err != nil || player == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	whispers, err := db.GetWhispersForPlayer(c, player.ID, whisperHistoryLimit)
+	if err != nil {
+		log.Printf("Error getting whispers for %s: %s", player.ID, err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get whispers",
+		})
+	}
+
+	return ctx.JSON(fiber.Map{
+		"whispers": whispers,
+	})
+}
@@ -2,6 +2,8 @@ package host
 
 import (
 	"wanshow-bingo/handlers/host/stream"
+	"wanshow-bingo/handlers/moderation"
+	"wanshow-bingo/middleware"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +14,21 @@ func init() {
 }
 
 func HostRouter(router fiber.Router) {
-	router.Get("/stream", stream.Get)
+	router.Get("/stream", middleware.AuthMiddleware, requireStreamAccess, stream.Get)
 	router.Post("/test-message", PostTestMessage)
 }
+
+// requireStreamAccess lets hosts, timer managers and moderators onto the host stream, so that
+// every client on it has a player for timer visibility and shadowed message delivery
+func requireStreamAccess(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(utils.NewApiError("Not authenticated", 401))
+	}
+
+	if !player.CanCreateTimers() && !moderation.CanModerate(player) {
+		return c.Status(fiber.StatusForbidden).JSON(utils.NewApiError("Host permission required", 403))
+	}
+
+	return c.Next()
+}
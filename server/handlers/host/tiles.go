@@ -4,10 +4,12 @@ import (
 	"context"
 	"log"
 	"time"
+	"wanshow-bingo/audit"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
 	"wanshow-bingo/middleware"
 	"wanshow-bingo/sse"
+	"wanshow-bingo/systemmsg"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -103,6 +105,8 @@ func CreateTile(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to create tile", 500))
 	}
 
+	audit.Record(c, audit.TileCreate, "tile", tile.ID, nil, tile)
+
 	return c.Status(fiber.StatusCreated).JSON(tile)
 }
 
@@ -129,6 +133,7 @@ func UpdateTile(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(utils.NewApiError("Tile not found", 404))
 	}
 	log.Printf("Existing tile settings: %+v", tile.Settings)
+	before := *tile
 
 	// Update fields
 	if req.Text != nil {
@@ -159,6 +164,7 @@ func UpdateTile(c *fiber.Ctx) error {
 	}
 
 	log.Printf("Tile updated successfully")
+	audit.Record(c, audit.TileUpdate, "tile", tile.ID, before, tile)
 
 	// Fetch the updated tile to verify
 	updatedTile, err := db.GetTileByID(ctx, tileID)
@@ -179,12 +185,19 @@ func DeleteTile(c *fiber.Ctx) error {
 
 	ctx := context.Background()
 
-	err := db.DeleteTile(ctx, tileID)
+	tile, err := db.GetTileByID(ctx, tileID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(utils.NewApiError("Tile not found", 404))
+	}
+
+	err = db.DeleteTile(ctx, tileID)
 	if err != nil {
 		log.Printf("Failed to delete tile: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to delete tile", 500))
 	}
 
+	audit.Record(c, audit.TileDelete, "tile", tileID, tile, nil)
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"success": true})
 }
 
@@ -288,6 +301,8 @@ func RevokeConfirmation(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to revoke confirmation", 500))
 	}
 
+	audit.Record(c, audit.TileRevoke, "tile", tileID, fiber.Map{"show_id": latestShow.ID, "confirmed": true}, fiber.Map{"show_id": latestShow.ID, "confirmed": false})
+
 	// Broadcast revoke
 	utils.Debugf("[HostTiles] RevokeConfirmation: revoking tile %s", tileID)
 	hostHub := sse.GetHostHub()
@@ -341,30 +356,16 @@ func PostTestMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	// Generate message ID
-	messageID, err := gonanoid.New(10)
+	latestShow, err := db.GetLatestShow(context.Background())
 	if err != nil {
-		log.Printf("Error generating message ID: %s", err)
+		log.Printf("Error getting latest show: %s", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate message ID",
+			"error": "Failed to save message",
 		})
 	}
 
-	// Create system message
-	message := &models.Message{
-		ID:        messageID,
-		ShowID:    "", // Will be set by SaveMessage to latest show
-		PlayerID:  "SYSTEM",
-		Contents:  req.Message,
-		System:    true,
-		Replying:  nil,
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		DeletedAt: nil,
-	}
-
-	// Save message to database
-	err = db.SaveMessage(context.Background(), message)
+	message, err := systemmsg.Post(context.Background(), latestShow.ID, "SYSTEM", models.SystemMessageAnnouncement,
+		systemmsg.Announcement(req.Message))
 	if err != nil {
 		log.Printf("Error saving test message: %s", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -372,16 +373,8 @@ func PostTestMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	// Broadcast message to chat hub
-	chatHub := sse.GetChatHub()
-	if chatHub != nil {
-		chatHub.BroadcastEvent("chat.message", message)
-	} else {
-		log.Printf("Warning: Chat hub not available for broadcasting test message")
-	}
-
 	return c.JSON(fiber.Map{
 		"success":    true,
-		"message_id": messageID,
+		"message_id": message.ID,
 	})
 }
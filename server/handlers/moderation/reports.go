@@ -0,0 +1,384 @@
+package moderation
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/sse"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// reportHideThreshold is how many players must report a message before it is hidden
+// until a moderator reviews it
+var reportHideThreshold = utils.GetEnvInt("REPORT_HIDE_THRESHOLD", 3)
+
+// maxReportDetailsLength is the longest explanation a player can attach to a report
+const maxReportDetailsLength = 500
+
+// FileReport reads the category and details of a report from the request and saves it. The
+// report must already name the reporter and the reported player, and message when a message
+// is reported, which is hidden once enough players have reported it.
+func FileReport(c *fiber.Ctx, report *models.Report, message *models.Message) error {
+	var req models.ReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if !req.Category.Valid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Report category must be spam, harassment, hate, sexual, spoilers or other",
+		})
+	}
+	req.Details = strings.TrimSpace(req.Details)
+	if utf8.RuneCountInString(req.Details) > maxReportDetailsLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Report details are too long",
+		})
+	}
+
+	if report.PlayerID == report.ReporterID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot report yourself",
+		})
+	}
+
+	report.Category = req.Category
+	if req.Details != "" {
+		report.Details = &req.Details
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	created, err := db.PersistReport(ctx, report)
+	if err != nil {
+		log.Printf("Error saving report: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save report",
+		})
+	}
+	if !created {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "You have already reported this",
+		})
+	}
+
+	if message != nil && message.HiddenAt == nil {
+		hideReportedMessage(ctx, message)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
+
+// hideReportedMessage hides a message from chat once enough players have reported it
+func hideReportedMessage(ctx context.Context, message *models.Message) {
+	count, err := db.CountOpenMessageReports(ctx, message.ID)
+	if err != nil {
+		log.Printf("Error counting reports of message %s: %s", message.ID, err)
+		return
+	}
+	if count < reportHideThreshold {
+		return
+	}
+
+	hidden, err := db.SetMessageHidden(ctx, message.ID, true)
+	if err != nil {
+		log.Printf("Error hiding message %s: %s", message.ID, err)
+		return
+	}
+	if !hidden {
+		return
+	}
+
+	log.Printf("Hid message %s after %d reports", message.ID, count)
+	if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.BroadcastEvent("chat.message.hidden", fiber.Map{
+			"id":      message.ID,
+			"show_id": message.ShowID,
+		})
+	}
+}
+
+// restoreMessage shows a hidden message again and sends it back to chat
+func restoreMessage(ctx context.Context, message *models.Message) {
+	restored, err := db.SetMessageHidden(ctx, message.ID, false)
+	if err != nil {
+		log.Printf("Error restoring message %s: %s", message.ID, err)
+		return
+	}
+	if !restored {
+		return
+	}
+
+	author, err := db.GetPlayerByID(ctx, message.PlayerID)
+	if err != nil {
+		log.Printf("Error getting author of message %s: %s", message.ID, err)
+		return
+	}
+
+	event := fiber.Map{
+		"id":         message.ID,
+		"show_id":    message.ShowID,
+		"player_id":  message.PlayerID,
+		"contents":   message.Contents,
+		"system":     message.System,
+		"replying":   message.Replying,
+		"mentions":   message.MentionIDs(),
+		"created_at": message.CreatedAt,
+		"updated_at": message.UpdatedAt,
+		"edited_at":  message.EditedAt,
+		"player":     author,
+	}
+	if message.Kind != nil {
+		event["kind"] = message.Kind
+		event["data"] = message.Data
+	}
+
+	// Shadowed messages were only ever shown to their author, and stay that way
+	if message.Shadowed {
+		SendShadowed("chat.message.restored", event, message.PlayerID, nil)
+	} else if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.BroadcastEvent("chat.message.restored", event)
+	}
+}
+
+// GetReports lists the open reports, grouped by the message or player reported and ordered
+// by when each was first reported
+func GetReports(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil || player == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !CanModerate(player) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reports, err := db.GetOpenReports(ctx)
+	if err != nil {
+		log.Printf("Error getting reports: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get reports",
+		})
+	}
+
+	groups := make([]*models.ReportGroup, 0)
+	byTarget := make(map[[2]string]*models.ReportGroup)
+	for _, report := range reports {
+		key := [2]string{report.PlayerID, ""}
+		if report.MessageID != nil {
+			key[1] = *report.MessageID
+		}
+
+		group, ok := byTarget[key]
+		if !ok {
+			group = &models.ReportGroup{
+				PlayerID:   report.PlayerID,
+				MessageID:  report.MessageID,
+				Categories: make(map[models.ReportCategory]int),
+			}
+			byTarget[key] = group
+			groups = append(groups, group)
+		}
+		group.Count++
+		group.Categories[report.Category]++
+		group.Reports = append(group.Reports, report)
+	}
+
+	// Moderators need the reported message itself, including hidden ones
+	for _, group := range groups {
+		if group.MessageID == nil {
+			continue
+		}
+		if message, err := db.GetMessageByID(ctx, *group.MessageID); err == nil {
+			group.Message = message
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"groups": groups,
+	})
+}
+
+// ResolveReport closes a report, and every other open report against the same message or
+// player, recording the action the moderator took. Any action other than dismissing deletes
+// a reported message; dismissing shows a hidden message again.
+func ResolveReport(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil || player == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	var req models.ResolveReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if !req.Action.Valid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Action must be dismiss, delete, mute, kick or ban",
+		})
+	}
+	if req.Scope == "" {
+		req.Scope = models.SanctionScopeGlobal
+	}
+	if !req.Scope.Valid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Sanction scope must be chat, suggestions or global",
+		})
+	}
+	if req.DurationSeconds < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Sanction duration cannot be negative",
+		})
+	}
+
+	sanctionType, sanctions := req.Action.SanctionType()
+	permission := models.PermCanModerate
+	if req.Action == models.ReportDelete {
+		permission = models.PermCanDeleteMessages
+	} else if sanctions {
+		permission = issuePermissions[sanctionType]
+	}
+	if !player.Permissions.HasPermission(permission) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report, err := db.GetReportByID(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Report not found",
+		})
+	}
+	if report.Status != models.ReportOpen {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Report has already been resolved",
+		})
+	}
+
+	if req.Action == models.ReportDelete && report.MessageID == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Only reported messages can be deleted",
+		})
+	}
+	// Sanctioning over a message report also deletes the message
+	if sanctions && report.MessageID != nil && !player.Permissions.HasPermission(models.PermCanDeleteMessages) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+	if sanctions && report.PlayerID == player.ID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot sanction yourself",
+		})
+	}
+
+	var reason *string
+	if req.Reason != "" {
+		reason = &req.Reason
+	}
+
+	var sanction *models.Sanction
+	if sanctions {
+		sanction = &models.Sanction{
+			PlayerID:  report.PlayerID,
+			Type:      sanctionType,
+			Scope:     req.Scope,
+			Reason:    reason,
+			IssuedBy:  &player.ID,
+			ExpiresAt: sanctionExpiry(sanctionType, req.DurationSeconds),
+		}
+		if err := IssueSanction(ctx, sanction); err != nil {
+			log.Printf("Error saving sanction: %s", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to save sanction",
+			})
+		}
+		audit.Record(c, audit.SanctionCreate, "player", report.PlayerID, nil, sanction)
+	}
+
+	// The reported message may already have been deleted by someone else
+	if report.MessageID != nil {
+		if message, err := db.GetMessageByID(ctx, *report.MessageID); err == nil {
+			if req.Action == models.ReportDismiss {
+				restoreMessage(ctx, message)
+			} else if err := deleteReportedMessage(ctx, message, player.ID, reason); err != nil {
+				log.Printf("Error deleting reported message %s: %s", message.ID, err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to delete message",
+				})
+			} else {
+				audit.Record(c, audit.MessageDelete, "message", message.ID, message, fiber.Map{"reason": reason})
+			}
+		}
+	}
+
+	var sanctionID *string
+	if sanction != nil {
+		sanctionID = &sanction.ID
+	}
+
+	reportIDs, err := db.ResolveReports(ctx, report, req.Action, sanctionID, player.ID)
+	if err != nil {
+		log.Printf("Error resolving report %s: %s", report.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to resolve report",
+		})
+	}
+
+	audit.Record(c, audit.ReportResolve, "report", report.ID, report, fiber.Map{
+		"action":      req.Action,
+		"report_ids":  reportIDs,
+		"sanction_id": sanctionID,
+	})
+
+	return c.JSON(fiber.Map{
+		"success":    true,
+		"action":     req.Action,
+		"report_ids": reportIDs,
+		"sanction":   sanction,
+	})
+}
+
+// deleteReportedMessage deletes a message a moderator upheld reports against
+func deleteReportedMessage(ctx context.Context, message *models.Message, deletedBy string, reason *string) error {
+	if err := db.DeleteMessage(ctx, message.ID, deletedBy, reason); err != nil {
+		return err
+	}
+
+	if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.BroadcastEvent("chat.message.deleted", fiber.Map{
+			"id":         message.ID,
+			"show_id":    message.ShowID,
+			"deleted_by": deletedBy,
+			"reason":     reason,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package moderation
+
+import (
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func init() {
+	utils.RegisterRouter("/moderation", BuildRouter)
+}
+
+func BuildRouter(router fiber.Router) {
+	auth := router.Group("", middleware.AuthMiddleware)
+	auth.Get("/sanctions", GetSanctions)
+	auth.Post("/sanctions", CreateSanction)
+	auth.Delete("/sanctions/:id", RevokeSanction)
+	auth.Get("/rules", GetRules)
+	auth.Post("/rules", CreateRule)
+	auth.Get("/rules/hits", GetRuleHits)
+	auth.Put("/rules/:id", UpdateRule)
+	auth.Delete("/rules/:id", DeleteRule)
+	auth.Put("/thresholds", UpdateThresholds)
+	auth.Get("/reports", GetReports)
+	auth.Post("/reports/:id/resolve", ResolveReport)
+}
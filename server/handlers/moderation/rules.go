@@ -0,0 +1,305 @@
+package moderation
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/automod"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requireModerator returns the player if they may manage moderation rules, responding
+// otherwise
+func requireModerator(c *fiber.Ctx) (*models.Player, error) {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil || player == nil {
+		return nil, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !player.Permissions.HasPermission(models.PermCanModerate) {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+
+	return player, nil
+}
+
+// reloadRules applies rule changes to the moderation pipeline straight away
+func reloadRules() {
+	if err := automod.Reload(); err != nil {
+		log.Printf("Error reloading moderation rules: %s", err)
+	}
+}
+
+// GetRules lists every moderation rule along with the thresholds
+func GetRules(c *fiber.Ctx) error {
+	player, errResp := requireModerator(c)
+	if player == nil {
+		return errResp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rules, err := db.GetModerationRules(ctx)
+	if err != nil {
+		log.Printf("Error getting moderation rules: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get moderation rules",
+		})
+	}
+
+	thresholds, err := db.GetModerationThresholds(ctx)
+	if err != nil {
+		log.Printf("Error getting moderation thresholds: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get moderation rules",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"rules":      rules,
+		"thresholds": thresholds,
+	})
+}
+
+// applyRuleRequest validates a rule request and copies it onto a rule, returning an error
+// message for the moderator if it is invalid
+func applyRuleRequest(rule *models.ModerationRule, req *models.ModerationRuleRequest) string {
+	if req.Kind != "" {
+		rule.Kind = req.Kind
+	}
+	if !rule.Kind.Valid() {
+		return "Rule kind must be term, regex or allow"
+	}
+
+	if req.Pattern != "" {
+		rule.Pattern = strings.TrimSpace(req.Pattern)
+	}
+	if rule.Pattern == "" {
+		return "Rule pattern is required"
+	}
+	if rule.Kind == models.ModerationRuleRegex {
+		if _, err := automod.CompileRegex(rule.Pattern); err != nil {
+			return "Invalid regular expression: " + err.Error()
+		}
+	}
+
+	if req.RuleID != "" {
+		rule.RuleID = req.RuleID
+	}
+	if rule.RuleID == "" {
+		rule.RuleID = string(rule.Kind) + ".custom"
+	}
+
+	if req.Reason != "" {
+		rule.Reason = &req.Reason
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	return ""
+}
+
+// CreateRule adds a blocked term, regex rule or allowlisted phrase
+func CreateRule(c *fiber.Ctx) error {
+	player, errResp := requireModerator(c)
+	if player == nil {
+		return errResp
+	}
+
+	var req models.ModerationRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rule := &models.ModerationRule{
+		Enabled:   true,
+		CreatedBy: &player.ID,
+	}
+	if errMsg := applyRuleRequest(rule, &req); errMsg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": errMsg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := db.PersistModerationRule(ctx, rule); err != nil {
+		log.Printf("Error saving moderation rule: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save moderation rule",
+		})
+	}
+
+	reloadRules()
+	audit.Record(c, audit.RuleCreate, "moderation_rule", rule.ID, nil, rule)
+
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+// UpdateRule changes a moderation rule, fields left out of the request are kept
+func UpdateRule(c *fiber.Ctx) error {
+	player, errResp := requireModerator(c)
+	if player == nil {
+		return errResp
+	}
+
+	var req models.ModerationRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rule, err := db.GetModerationRuleByID(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Moderation rule not found",
+		})
+	}
+
+	before := *rule
+	if errMsg := applyRuleRequest(rule, &req); errMsg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": errMsg,
+		})
+	}
+
+	if err := db.PersistModerationRule(ctx, rule); err != nil {
+		log.Printf("Error saving moderation rule %s: %s", rule.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save moderation rule",
+		})
+	}
+
+	reloadRules()
+	audit.Record(c, audit.RuleUpdate, "moderation_rule", rule.ID, before, rule)
+
+	return c.JSON(rule)
+}
+
+// DeleteRule removes a moderation rule
+func DeleteRule(c *fiber.Ctx) error {
+	player, errResp := requireModerator(c)
+	if player == nil {
+		return errResp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	ruleID := c.Params("id")
+	rule, err := db.GetModerationRuleByID(ctx, ruleID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Moderation rule not found",
+		})
+	}
+
+	if err := db.DeleteModerationRule(ctx, ruleID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Moderation rule not found",
+		})
+	}
+
+	reloadRules()
+	audit.Record(c, audit.RuleDelete, "moderation_rule", ruleID, rule, nil)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"rule_id": ruleID,
+	})
+}
+
+// UpdateThresholds changes the caps and repeated character limits
+func UpdateThresholds(c *fiber.Ctx) error {
+	player, errResp := requireModerator(c)
+	if player == nil {
+		return errResp
+	}
+
+	var thresholds models.ModerationThresholds
+	if err := c.BodyParser(&thresholds); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if thresholds.CapsRatio <= 0 || thresholds.CapsRatio > 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "caps_ratio must be between 0 and 1",
+		})
+	}
+	if thresholds.CapsMinLength < 0 || thresholds.RepeatLimit < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Thresholds cannot be negative",
+		})
+	}
+	thresholds.UpdatedBy = &player.ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	before, err := db.GetModerationThresholds(ctx)
+	if err != nil {
+		log.Printf("Error getting moderation thresholds: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save moderation thresholds",
+		})
+	}
+
+	if err := db.PersistModerationThresholds(ctx, &thresholds); err != nil {
+		log.Printf("Error saving moderation thresholds: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save moderation thresholds",
+		})
+	}
+
+	reloadRules()
+	audit.Record(c, audit.ThresholdsUpdate, "moderation_thresholds", "", before, thresholds)
+
+	return c.JSON(thresholds)
+}
+
+// GetRuleHits lists how often each rule rejected a message, most hit first
+func GetRuleHits(c *fiber.Ctx) error {
+	player, errResp := requireModerator(c)
+	if player == nil {
+		return errResp
+	}
+
+	// Include the hits counted since the last periodic flush
+	automod.FlushHits()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	hits, err := db.GetModerationRuleHits(ctx)
+	if err != nil {
+		log.Printf("Error getting moderation rule hits: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get moderation rule hits",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"hits": hits,
+	})
+}
@@ -0,0 +1,251 @@
+package moderation
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/audit"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultKickDuration is how long a kicked player stays out when no duration is given
+const defaultKickDuration = 5 * time.Minute
+
+// issuePermissions maps each sanction type to the permission needed to issue it
+var issuePermissions = map[models.SanctionType]models.Permission{
+	models.SanctionMute:       models.PermCanMuteUsers,
+	models.SanctionShadowMute: models.PermCanMuteUsers,
+	models.SanctionKick:       models.PermCanKickUsers,
+	models.SanctionBan:        models.PermCanBanUsers,
+}
+
+// revokePermissions maps each sanction type to the permission needed to lift it early
+var revokePermissions = map[models.SanctionType]models.Permission{
+	models.SanctionMute:       models.PermCanUnmuteUsers,
+	models.SanctionShadowMute: models.PermCanUnmuteUsers,
+	models.SanctionKick:       models.PermCanKickUsers,
+	models.SanctionBan:        models.PermCanBanUsers,
+}
+
+// CanModerate reports whether a player may issue or view any kind of sanction
+func CanModerate(player *models.Player) bool {
+	if player.Permissions.HasPermission(models.PermCanModerate) {
+		return true
+	}
+	for _, perm := range issuePermissions {
+		if player.Permissions.HasPermission(perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanctionExpiry returns when a sanction of the given type and duration ends. Kicks are
+// always temporary, mutes and bans without a duration last until revoked.
+func sanctionExpiry(sanctionType models.SanctionType, durationSeconds int) *time.Time {
+	duration := time.Duration(durationSeconds) * time.Second
+	if duration == 0 && sanctionType == models.SanctionKick {
+		duration = defaultKickDuration
+	}
+	if duration <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().Add(duration)
+	return &expiresAt
+}
+
+// IssueSanction saves a sanction and applies it to the player's live streams
+func IssueSanction(ctx context.Context, sanction *models.Sanction) error {
+	if err := db.PersistSanction(ctx, sanction); err != nil {
+		return err
+	}
+
+	// Muted players are told so their client can disable the input, kicks and bans
+	// from chat take effect immediately on live streams. Shadow muted players are never told.
+	if sanction.Type == models.SanctionShadowMute {
+		return nil
+	}
+	if !sanction.Type.Disconnects() {
+		if chatHub := sse.GetChatHub(); chatHub != nil {
+			chatHub.SendEventToPlayers("chat.sanctioned", sanction, sanction.PlayerID)
+		}
+	} else if sanction.Scope.Covers(models.SanctionScopeChat) {
+		reason := string(sanction.Type)
+		if sanction.Reason != nil {
+			reason += ": " + *sanction.Reason
+		}
+		if chatHub := sse.GetChatHub(); chatHub != nil {
+			chatHub.DisconnectPlayer(sanction.PlayerID, reason)
+		}
+		if hostHub := sse.GetHostHub(); hostHub != nil {
+			hostHub.DisconnectPlayer(sanction.PlayerID, reason)
+		}
+	}
+
+	return nil
+}
+
+// GetSanctions lists active sanctions, optionally for a single player
+func GetSanctions(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil || player == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	if !CanModerate(player) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	sanctions, err := db.GetActiveSanctions(ctx, c.Query("player_id"))
+	if err != nil {
+		log.Printf("Error getting sanctions: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get sanctions",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"sanctions": sanctions,
+	})
+}
+
+// CreateSanction mutes, kicks or bans a player
+func CreateSanction(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil || player == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	var req models.SanctionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if !req.Type.Valid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Sanction type must be mute, shadow_mute, kick or ban",
+		})
+	}
+	if req.Scope == "" {
+		req.Scope = models.SanctionScopeGlobal
+		if req.Type == models.SanctionShadowMute {
+			req.Scope = models.SanctionScopeChat
+		}
+	}
+	if req.Type == models.SanctionShadowMute && req.Scope != models.SanctionScopeChat {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Shadow mutes only apply to chat",
+		})
+	}
+	if !req.Scope.Valid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Sanction scope must be chat, suggestions or global",
+		})
+	}
+	if req.DurationSeconds < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Sanction duration cannot be negative",
+		})
+	}
+
+	if !player.Permissions.HasPermission(issuePermissions[req.Type]) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	target, err := db.GetPlayerByIdentifier(ctx, req.Player)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Player not found",
+		})
+	}
+
+	if target.ID == player.ID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot sanction yourself",
+		})
+	}
+
+	sanction := &models.Sanction{
+		PlayerID: target.ID,
+		Type:     req.Type,
+		Scope:    req.Scope,
+		IssuedBy: &player.ID,
+	}
+	if req.Reason != "" {
+		sanction.Reason = &req.Reason
+	}
+
+	sanction.ExpiresAt = sanctionExpiry(req.Type, req.DurationSeconds)
+
+	if err := IssueSanction(ctx, sanction); err != nil {
+		log.Printf("Error saving sanction: %s", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save sanction",
+		})
+	}
+
+	audit.Record(c, audit.SanctionCreate, "player", target.ID, nil, sanction)
+
+	return c.Status(fiber.StatusCreated).JSON(sanction)
+}
+
+// RevokeSanction lifts a sanction before it expires
+func RevokeSanction(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil || player == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	sanction, err := db.GetSanctionByID(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Sanction not found",
+		})
+	}
+
+	if !player.Permissions.HasPermission(revokePermissions[sanction.Type]) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+
+	if err := db.RevokeSanction(ctx, sanction.ID, player.ID); err != nil {
+		log.Printf("Error revoking sanction %s: %s", sanction.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke sanction",
+		})
+	}
+
+	audit.Record(c, audit.SanctionRevoke, "sanction", sanction.ID, sanction, nil)
+
+	return c.JSON(fiber.Map{
+		"success":     true,
+		"sanction_id": sanction.ID,
+	})
+}
@@ -0,0 +1,33 @@
+package moderation
+
+import (
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/sse"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SendShadowed echoes an event about a shadowed message back to its author only, and flags it
+// to the moderators connected to the host hub. sanction is the shadow mute the message was sent
+// under, if it is known.
+func SendShadowed(eventName string, data fiber.Map, authorID string, sanction *models.Sanction) {
+	if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.SendEventToPlayers(eventName, data, authorID)
+	}
+
+	hostHub := sse.GetHostHub()
+	if hostHub == nil {
+		return
+	}
+
+	flagged := fiber.Map{"shadowed": true}
+	for key, value := range data {
+		flagged[key] = value
+	}
+	if sanction != nil {
+		flagged["sanction_id"] = sanction.ID
+	}
+	hostHub.BroadcastEventWhere(eventName, flagged, func(c *sse.Client) bool {
+		return c.Player != nil && CanModerate(c.Player)
+	})
+}
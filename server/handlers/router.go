@@ -6,6 +6,7 @@ import (
 	_ "wanshow-bingo/handlers/auth"
 	_ "wanshow-bingo/handlers/chat"
 	_ "wanshow-bingo/handlers/host"
+	_ "wanshow-bingo/handlers/moderation"
 	_ "wanshow-bingo/handlers/show"
 	_ "wanshow-bingo/handlers/suggestions"
 	_ "wanshow-bingo/handlers/tiles"
@@ -0,0 +1,306 @@
+package show
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// exportTimeout bounds how long streaming a transcript may take
+	exportTimeout = 5 * time.Minute
+	// exportFlushEvery is how many messages are written between flushes to the client
+	exportFlushEvery = 200
+	// transcriptTimeFormat is how absolute times are written in CSV and markdown transcripts
+	transcriptTimeFormat = "2006-01-02 15:04:05"
+)
+
+// transcriptWriter writes a chat transcript in one format, one message at a time
+type transcriptWriter interface {
+	Begin(show *models.Show) error
+	Write(entry *models.TranscriptEntry) error
+	End() error
+}
+
+// transcriptFormat is an export format and how it is served
+type transcriptFormat struct {
+	contentType string
+	extension   string
+	new         func(w *bufio.Writer) transcriptWriter
+}
+
+var transcriptFormats = map[string]transcriptFormat{
+	"json": {
+		contentType: fiber.MIMEApplicationJSONCharsetUTF8,
+		extension:   "json",
+		new:         func(w *bufio.Writer) transcriptWriter { return &jsonTranscript{w: w} },
+	},
+	"ndjson": {
+		contentType: "application/x-ndjson",
+		extension:   "ndjson",
+		new:         func(w *bufio.Writer) transcriptWriter { return &ndjsonTranscript{w: w} },
+	},
+	"csv": {
+		contentType: "text/csv; charset=utf-8",
+		extension:   "csv",
+		new:         func(w *bufio.Writer) transcriptWriter { return &csvTranscript{w: csv.NewWriter(w)} },
+	},
+	"markdown": {
+		contentType: "text/markdown; charset=utf-8",
+		extension:   "md",
+		new:         func(w *bufio.Writer) transcriptWriter { return &markdownTranscript{w: w} },
+	},
+}
+
+// ExportChat streams the chat transcript of a show as JSON, NDJSON, CSV or markdown
+func ExportChat(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if id == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Show ID is required")
+	}
+
+	formatName := ctx.Query("format", "json")
+	format, ok := transcriptFormats[formatName]
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Format must be json, ndjson, csv or markdown")
+	}
+	omitDeleted := ctx.QueryBool("omit_deleted")
+
+	show, err := db.GetShowByID(context.Background(), id)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Show not found")
+	}
+
+	ctx.Set(fiber.HeaderContentType, format.contentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="show-%s-chat.%s"`, show.ID, format.extension))
+
+	// The response is written after the handler returns, so the status is already sent and
+	// failures part way through can only be logged
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		c, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		out := format.new(w)
+		if err := out.Begin(show); err != nil {
+			log.Printf("Error exporting chat of show %s: %s", show.ID, err)
+			return
+		}
+
+		written := 0
+		err := db.StreamShowTranscript(c, show.ID, omitDeleted, func(entry *models.TranscriptEntry) error {
+			if err := out.Write(entry); err != nil {
+				return err
+			}
+			written++
+			if written%exportFlushEvery == 0 {
+				return w.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Error exporting chat of show %s after %d messages: %s", show.ID, written, err)
+		}
+
+		if err := out.End(); err != nil {
+			log.Printf("Error exporting chat of show %s: %s", show.ID, err)
+		}
+		w.Flush()
+	})
+
+	return nil
+}
+
+// jsonTranscript writes a single object holding the show and an array of its messages
+type jsonTranscript struct {
+	w       *bufio.Writer
+	written bool
+}
+
+func (t *jsonTranscript) Begin(show *models.Show) error {
+	data, err := json.Marshal(show)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(t.w, `{"show":%s,"messages":[`, data)
+	return err
+}
+
+func (t *jsonTranscript) Write(entry *models.TranscriptEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if t.written {
+		t.w.WriteByte(',')
+	}
+	t.written = true
+	_, err = t.w.Write(data)
+	return err
+}
+
+func (t *jsonTranscript) End() error {
+	_, err := t.w.WriteString("]}")
+	return err
+}
+
+// ndjsonTranscript writes one message per line, without the show
+type ndjsonTranscript struct {
+	w *bufio.Writer
+}
+
+func (t *ndjsonTranscript) Begin(show *models.Show) error {
+	return nil
+}
+
+func (t *ndjsonTranscript) Write(entry *models.TranscriptEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	t.w.Write(data)
+	return t.w.WriteByte('\n')
+}
+
+func (t *ndjsonTranscript) End() error {
+	return nil
+}
+
+// csvTranscript writes a header row followed by one row per message, times in UTC
+type csvTranscript struct {
+	w *csv.Writer
+}
+
+func (t *csvTranscript) Begin(show *models.Show) error {
+	return t.w.Write([]string{
+		"id", "created_at", "offset_seconds", "offset", "player_id", "display_name", "system", "kind", "contents",
+		"replying", "edited_at", "deleted_at", "deleted_by", "delete_reason", "hidden", "shadowed",
+	})
+}
+
+func (t *csvTranscript) Write(entry *models.TranscriptEntry) error {
+	offsetSeconds := ""
+	if entry.OffsetSeconds != nil {
+		offsetSeconds = strconv.FormatInt(*entry.OffsetSeconds, 10)
+	}
+
+	t.w.Write([]string{
+		entry.ID,
+		entry.CreatedAt.UTC().Format(time.RFC3339),
+		offsetSeconds,
+		entry.Offset(),
+		entry.PlayerID,
+		optional(entry.DisplayName),
+		strconv.FormatBool(entry.System),
+		optionalKind(entry.Kind),
+		entry.Contents,
+		optional(entry.Replying),
+		optionalTime(entry.EditedAt),
+		optionalTime(entry.DeletedAt),
+		optional(entry.DeletedBy),
+		optional(entry.DeleteReason),
+		strconv.FormatBool(entry.Hidden),
+		strconv.FormatBool(entry.Shadowed),
+	})
+	return t.w.Error()
+}
+
+func (t *csvTranscript) End() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// markdownTranscript writes a readable list of messages, one item per message
+type markdownTranscript struct {
+	w *bufio.Writer
+}
+
+func (t *markdownTranscript) Begin(show *models.Show) error {
+	heading := "Chat transcript"
+	if title, ok := show.Metadata["title"].(string); ok && title != "" {
+		heading += ": " + title
+	}
+	fmt.Fprintf(t.w, "# %s\n\n- Show: `%s`\n", heading, show.ID)
+	if show.YoutubeID != nil {
+		fmt.Fprintf(t.w, "- Video: https://www.youtube.com/watch?v=%s\n", *show.YoutubeID)
+	}
+	if show.ActualStartTime != nil {
+		fmt.Fprintf(t.w, "- Started: %s UTC\n", show.ActualStartTime.UTC().Format(transcriptTimeFormat))
+	}
+	_, err := fmt.Fprintf(t.w, "- Exported: %s UTC\n\n", time.Now().UTC().Format(transcriptTimeFormat))
+	return err
+}
+
+func (t *markdownTranscript) Write(entry *models.TranscriptEntry) error {
+	fmt.Fprintf(t.w, "- `%s UTC`", entry.CreatedAt.UTC().Format(transcriptTimeFormat))
+	if offset := entry.Offset(); offset != "" {
+		fmt.Fprintf(t.w, " `%s`", offset)
+	}
+
+	author := entry.PlayerID
+	if entry.DisplayName != nil {
+		author = *entry.DisplayName
+	}
+	if entry.System {
+		fmt.Fprintf(t.w, " *System (%s)*: ", author)
+	} else {
+		fmt.Fprintf(t.w, " **%s**: ", author)
+	}
+
+	// Continuation lines are indented to stay inside the list item
+	contents := strings.ReplaceAll(entry.Contents, "\n", "\n  ")
+	if entry.DeletedAt != nil {
+		t.w.WriteString("~~" + contents + "~~ *(deleted")
+		if entry.DeleteReason != nil {
+			t.w.WriteString(": " + *entry.DeleteReason)
+		}
+		t.w.WriteString(")*")
+	} else {
+		t.w.WriteString(contents)
+	}
+	if entry.EditedAt != nil {
+		t.w.WriteString(" *(edited)*")
+	}
+	if entry.Hidden {
+		t.w.WriteString(" *(hidden by reports)*")
+	}
+	if entry.Shadowed {
+		t.w.WriteString(" *(shadow muted)*")
+	}
+
+	return t.w.WriteByte('\n')
+}
+
+func (t *markdownTranscript) End() error {
+	return nil
+}
+
+func optional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func optionalKind(value *models.SystemMessageKind) string {
+	if value == nil {
+		return ""
+	}
+	return string(*value)
+}
+
+func optionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -3,6 +3,7 @@ package show
 import (
 	"context"
 	"wanshow-bingo/db"
+	"wanshow-bingo/middleware"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,6 +16,7 @@ func init() {
 func Register(router fiber.Router) {
 	router.Get("/latest", GetLatest)
 	router.Get("/:id", GetByID)
+	router.Get("/:id/chat/export", middleware.AuthMiddleware, middleware.RequirePermissionMiddleware("can_moderate"), ExportChat)
 }
 
 func GetLatest(ctx *fiber.Ctx) error {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"time"
+	"wanshow-bingo/audit"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
 	"wanshow-bingo/middleware"
@@ -17,7 +18,7 @@ func init() {
 }
 
 func BuildRouter(router fiber.Router) {
-	router.Post("/", CreateSuggestion)
+	router.Post("/", middleware.OptionalPlayerAuthMiddleware, CreateSuggestion)
 	router.Get("/", middleware.AuthMiddleware, GetSuggestions)
 	router.Put("/:id", middleware.AuthMiddleware, UpdateSuggestion)
 }
@@ -37,6 +38,16 @@ func CreateSuggestion(c *fiber.Ctx) error {
 		return utils.NewApiError("Invalid request body", 0x0601).AsResponse(c)
 	}
 
+	// Signed in players who are muted or banned from suggestions may not submit them
+	if player, ok := c.Locals("player").(*models.Player); ok {
+		sanction, err := db.GetActiveSanction(ctx, player.ID, models.SanctionScopeSuggestions, models.SanctionMute, models.SanctionBan)
+		if err != nil {
+			log.Printf("failed to check sanctions for %s: %v", player.ID, err)
+		} else if sanction != nil {
+			return utils.NewApiError("You cannot submit suggestions while sanctioned", 0x060A).AsResponse(c)
+		}
+	}
+
 	if req.Name == "" || req.TileName == "" || req.Reason == "" {
 		return utils.NewApiError("Name, tile name, and reason are required", 0x0602).AsResponse(c)
 	}
@@ -109,6 +120,16 @@ func UpdateSuggestion(c *fiber.Ctx) error {
 		return utils.NewApiError("Status is required", 0x0607).AsResponse(c)
 	}
 
+	previous, err := db.GetTileSuggestionByID(ctx, id)
+	if err != nil {
+		log.Printf("failed to get suggestion: %v", err)
+		return utils.NewApiError("Failed to update suggestion", 0x0608).AsResponse(c)
+	}
+
+	if previous == nil {
+		return utils.NewApiError("Suggestion not found", 0x0609).AsResponse(c)
+	}
+
 	suggestion, err := db.UpdateTileSuggestion(ctx, id, req.Status, &player.ID)
 	if err != nil {
 		log.Printf("failed to update suggestion: %v", err)
@@ -119,5 +140,7 @@ func UpdateSuggestion(c *fiber.Ctx) error {
 		return utils.NewApiError("Suggestion not found", 0x0609).AsResponse(c)
 	}
 
+	audit.Record(c, audit.SuggestionReview, "suggestion", suggestion.ID, previous, suggestion)
+
 	return c.JSON(suggestion)
 }
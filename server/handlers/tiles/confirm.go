@@ -4,14 +4,15 @@ import (
 	"context"
 	"log"
 	"time"
+	"wanshow-bingo/audit"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
 	"wanshow-bingo/middleware"
 	"wanshow-bingo/sse"
+	"wanshow-bingo/systemmsg"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
@@ -73,6 +74,8 @@ func ConfirmTile(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to save confirmation", 500))
 	}
 
+	audit.Record(c, audit.TileConfirm, "tile", req.TileID, nil, confirmation)
+
 	// Get the tile details for the message
 	tile, err := db.GetTileByID(ctx, req.TileID)
 	if err != nil {
@@ -80,36 +83,14 @@ func ConfirmTile(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to get tile details", 500))
 	}
 
-	// Create system message
-	messageContent := "**TILE CONFIRMED** " + tile.Title
-	if req.Context != "" {
-		messageContent = "**TILE CONFIRMED**\n\n" + tile.Title + "\n\n> Context: " + req.Context
-	}
-
-	systemMessage := &models.Message{
-		ID:        uuid.New().String(),
-		ShowID:    latestShow.ID,
-		PlayerID:  player.ID,
-		Contents:  messageContent,
-		System:    true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Save message
-	err = db.PersistMessage(ctx, systemMessage)
+	// Announce the confirmation in chat
+	_, err = systemmsg.Post(ctx, latestShow.ID, player.ID, models.SystemMessageTileConfirmed,
+		systemmsg.TileConfirmed(tile, player.ID, "", req.Context))
 	if err != nil {
+		log.Printf("Failed to send tile confirmation message: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to send system message", 500))
 	}
 
-	// Broadcast message to chat hub
-	chatHub := sse.GetChatHub()
-	if chatHub != nil {
-		chatHub.BroadcastEvent("chat.message", systemMessage)
-	} else {
-		log.Printf("Warning: Chat hub not available for broadcasting")
-	}
-
 	// Broadcast tile confirmation to host hub
 	utils.Debugf("[TileConfirm] Broadcasting tile confirmation for tile %s", req.TileID)
 	hostHub := sse.GetHostHub()
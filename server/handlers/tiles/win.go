@@ -3,15 +3,14 @@ package tilerouter
 import (
 	"context"
 	"log"
-	"time"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
 	"wanshow-bingo/middleware"
 	"wanshow-bingo/sse"
+	"wanshow-bingo/systemmsg"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 func RecordWin(c *fiber.Ctx) error {
@@ -45,34 +44,13 @@ func RecordWin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to record win", 500))
 	}
 
-	// Create system message for the win
-	messageContent := "**BINGO WINNER!** " + player.DisplayName + " has won the bingo game!"
-
-	systemMessage := &models.Message{
-		ID:        uuid.New().String(),
-		ShowID:    latestShow.ID,
-		PlayerID:  player.ID,
-		Contents:  messageContent,
-		System:    true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Save message
-	err = db.PersistMessage(ctx, systemMessage)
+	// Announce the win in chat
+	_, err = systemmsg.Post(ctx, latestShow.ID, player.ID, models.SystemMessageWinner, systemmsg.Winner(player, board.ID))
 	if err != nil {
 		log.Printf("Failed to send win system message: %v", err)
 		// Don't fail the request for this
 	}
 
-	// Broadcast message to chat hub
-	chatHub := sse.GetChatHub()
-	if chatHub != nil {
-		chatHub.BroadcastEvent("chat.message", systemMessage)
-	} else {
-		log.Printf("Warning: Chat hub not available for broadcasting win message")
-	}
-
 	// Broadcast win event to host hub
 	hostHub := sse.GetHostHub()
 	if hostHub != nil {
@@ -0,0 +1,15 @@
+package timers
+
+import (
+	"wanshow-bingo/db/models"
+)
+
+// canManageTimer reports whether a player may modify a timer. Players with the
+// timer management permission can manage any timer, including system timers
+// which have no creator; everyone else can only manage their own timers.
+func canManageTimer(player *models.Player, timer *models.Timer) bool {
+	if player.Permissions.HasPermission(models.PermCanManageTimers) {
+		return true
+	}
+	return timer.CreatedBy != nil && *timer.CreatedBy == player.ID
+}
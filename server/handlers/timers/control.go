@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"time"
+	"wanshow-bingo/audit"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
 	"wanshow-bingo/utils"
@@ -27,15 +28,15 @@ func StartTimer(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	// Get existing timer to check ownership
+	// Get existing timer to check access
 	existingTimer, err := db.GetTimerByID(ctx, timerID)
 	if err != nil {
 		log.Printf("failed to get timer %s: %v", timerID, err)
 		return utils.NewApiError("Timer not found", 0x0753).AsResponse(c)
 	}
 
-	// Check if user owns this timer
-	if existingTimer.CreatedBy == nil || *existingTimer.CreatedBy != player.ID {
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
 		return utils.NewApiError("Access denied", 0x0754).AsResponse(c)
 	}
 
@@ -52,6 +53,9 @@ func StartTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Timer started but failed to retrieve", 0x0756).AsResponse(c)
 	}
 
+	audit.Record(c, audit.TimerStart, "timer", timerID, existingTimer, updatedTimer)
+	broadcastTimerEvent("timer.started", updatedTimer)
+
 	return c.JSON(updatedTimer)
 }
 
@@ -71,18 +75,45 @@ func StopTimer(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	// Get existing timer to check ownership
+	// Get existing timer to check access
 	existingTimer, err := db.GetTimerByID(ctx, timerID)
 	if err != nil {
 		log.Printf("failed to get timer %s: %v", timerID, err)
 		return utils.NewApiError("Timer not found", 0x0763).AsResponse(c)
 	}
 
-	// Check if user owns this timer
-	if existingTimer.CreatedBy == nil || *existingTimer.CreatedBy != player.ID {
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
 		return utils.NewApiError("Access denied", 0x0764).AsResponse(c)
 	}
 
+	// Stopwatches record their final value for the show when stopped
+	if existingTimer.Mode == models.TimerModeStopwatch && existingTimer.IsActive {
+		result, err := db.StopStopwatch(ctx, timerID, &player.ID)
+		if err != nil {
+			log.Printf("failed to stop stopwatch: %v", err)
+			return utils.NewApiError("Failed to stop timer", 0x0767).AsResponse(c)
+		}
+
+		updatedTimer, err := db.GetTimerByID(ctx, timerID)
+		if err != nil {
+			log.Printf("failed to get updated timer: %v", err)
+			return utils.NewApiError("Timer stopped but failed to retrieve", 0x0768).AsResponse(c)
+		}
+
+		audit.Record(c, audit.TimerStop, "timer", timerID, existingTimer, fiber.Map{
+			"timer":  updatedTimer,
+			"result": result,
+		})
+		broadcastTimerEvent("timer.stopped", updatedTimer)
+		broadcastTimerResult(updatedTimer, result)
+
+		return c.JSON(fiber.Map{
+			"timer":  updatedTimer,
+			"result": result,
+		})
+	}
+
 	err = db.StopTimer(ctx, timerID)
 	if err != nil {
 		log.Printf("failed to stop timer: %v", err)
@@ -96,6 +127,9 @@ func StopTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Timer stopped but failed to retrieve", 0x0766).AsResponse(c)
 	}
 
+	audit.Record(c, audit.TimerStop, "timer", timerID, existingTimer, updatedTimer)
+	broadcastTimerEvent("timer.stopped", updatedTimer)
+
 	return c.JSON(updatedTimer)
 }
 
@@ -115,15 +149,15 @@ func ResetTimer(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	// Get existing timer to check ownership
+	// Get existing timer to check access
 	existingTimer, err := db.GetTimerByID(ctx, timerID)
 	if err != nil {
 		log.Printf("failed to get timer %s: %v", timerID, err)
 		return utils.NewApiError("Timer not found", 0x0773).AsResponse(c)
 	}
 
-	// Check if user owns this timer
-	if existingTimer.CreatedBy == nil || *existingTimer.CreatedBy != player.ID {
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
 		return utils.NewApiError("Access denied", 0x0774).AsResponse(c)
 	}
 
@@ -141,5 +175,163 @@ func ResetTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Timer reset but failed to retrieve", 0x0776).AsResponse(c)
 	}
 
+	audit.Record(c, audit.TimerReset, "timer", timerID, existingTimer, updatedTimer)
+	broadcastTimerEvent("timer.started", updatedTimer)
+
+	return c.JSON(updatedTimer)
+}
+
+// PauseTimer freezes a running timer, keeping its remaining duration
+func PauseTimer(c *fiber.Ctx) error {
+	timerID := c.Params("id")
+	if timerID == "" {
+		return utils.NewApiError("Timer ID is required", 0x0781).AsResponse(c)
+	}
+
+	// Get authenticated player
+	player, ok := c.Locals("player").(*models.Player)
+	if !ok {
+		return utils.NewApiError("Authentication required", 0x0782).AsResponse(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Get existing timer to check access
+	existingTimer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get timer %s: %v", timerID, err)
+		return utils.NewApiError("Timer not found", 0x0783).AsResponse(c)
+	}
+
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
+		return utils.NewApiError("Access denied", 0x0784).AsResponse(c)
+	}
+
+	err = db.PauseTimer(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to pause timer: %v", err)
+		return utils.NewApiError("Failed to pause timer: "+err.Error(), 0x0785).AsResponse(c)
+	}
+
+	// Get updated timer
+	updatedTimer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get updated timer: %v", err)
+		return utils.NewApiError("Timer paused but failed to retrieve", 0x0786).AsResponse(c)
+	}
+
+	audit.Record(c, audit.TimerPause, "timer", timerID, existingTimer, updatedTimer)
+	broadcastTimerEvent("timer.updated", updatedTimer)
+
+	return c.JSON(updatedTimer)
+}
+
+// ResumeTimer restarts a paused timer from its remaining duration
+func ResumeTimer(c *fiber.Ctx) error {
+	timerID := c.Params("id")
+	if timerID == "" {
+		return utils.NewApiError("Timer ID is required", 0x0791).AsResponse(c)
+	}
+
+	// Get authenticated player
+	player, ok := c.Locals("player").(*models.Player)
+	if !ok {
+		return utils.NewApiError("Authentication required", 0x0792).AsResponse(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Get existing timer to check access
+	existingTimer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get timer %s: %v", timerID, err)
+		return utils.NewApiError("Timer not found", 0x0793).AsResponse(c)
+	}
+
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
+		return utils.NewApiError("Access denied", 0x0794).AsResponse(c)
+	}
+
+	err = db.ResumeTimer(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to resume timer: %v", err)
+		return utils.NewApiError("Failed to resume timer: "+err.Error(), 0x0795).AsResponse(c)
+	}
+
+	// Get updated timer
+	updatedTimer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get updated timer: %v", err)
+		return utils.NewApiError("Timer resumed but failed to retrieve", 0x0796).AsResponse(c)
+	}
+
+	audit.Record(c, audit.TimerResume, "timer", timerID, existingTimer, updatedTimer)
+	broadcastTimerEvent("timer.updated", updatedTimer)
+
+	return c.JSON(updatedTimer)
+}
+
+// ExtendTimerRequest is the body of POST /timers/:id/extend
+type ExtendTimerRequest struct {
+	// Seconds to add to the timer, negative values shorten it
+	Seconds int `json:"seconds"`
+}
+
+// ExtendTimer extends or shortens an active timer by a number of seconds
+func ExtendTimer(c *fiber.Ctx) error {
+	timerID := c.Params("id")
+	if timerID == "" {
+		return utils.NewApiError("Timer ID is required", 0x07A1).AsResponse(c)
+	}
+
+	// Get authenticated player
+	player, ok := c.Locals("player").(*models.Player)
+	if !ok {
+		return utils.NewApiError("Authentication required", 0x07A2).AsResponse(c)
+	}
+
+	var req ExtendTimerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.NewApiError("Invalid request body", 0x07A3).AsResponse(c)
+	}
+	if req.Seconds == 0 {
+		return utils.NewApiError("Seconds must not be zero", 0x07A4).AsResponse(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Get existing timer to check access
+	existingTimer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get timer %s: %v", timerID, err)
+		return utils.NewApiError("Timer not found", 0x07A5).AsResponse(c)
+	}
+
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
+		return utils.NewApiError("Access denied", 0x07A6).AsResponse(c)
+	}
+
+	err = db.AdjustTimer(ctx, timerID, req.Seconds)
+	if err != nil {
+		log.Printf("failed to extend timer: %v", err)
+		return utils.NewApiError("Failed to extend timer: "+err.Error(), 0x07A7).AsResponse(c)
+	}
+
+	// Get updated timer
+	updatedTimer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get updated timer: %v", err)
+		return utils.NewApiError("Timer extended but failed to retrieve", 0x07A8).AsResponse(c)
+	}
+
+	audit.Record(c, audit.TimerExtend, "timer", timerID, existingTimer, updatedTimer)
+	broadcastTimerEvent("timer.updated", updatedTimer)
+
 	return c.JSON(updatedTimer)
 }
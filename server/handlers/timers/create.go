@@ -4,8 +4,10 @@ import (
 	"context"
 	"log"
 	"time"
+	"wanshow-bingo/audit"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
+	"wanshow-bingo/sse"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,6 +21,11 @@ func CreateTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Authentication required", 0x0721).AsResponse(c)
 	}
 
+	// Timers are visible to the whole show, so only hosts and timer managers may create them
+	if !player.CanCreateTimers() {
+		return utils.NewApiError("Host or timer management permission required", 0x0729).AsResponse(c)
+	}
+
 	var timer models.Timer
 	if err := c.BodyParser(&timer); err != nil {
 		return utils.NewApiError("Invalid request body", 0x0722).AsResponse(c)
@@ -28,19 +35,37 @@ func CreateTimer(c *fiber.Ctx) error {
 	if timer.Title == "" {
 		return utils.NewApiError("Timer title is required", 0x0723).AsResponse(c)
 	}
-	if timer.Duration <= 0 {
+	if timer.Mode == "" {
+		timer.Mode = models.TimerModeCountdown
+	}
+	if timer.Mode != models.TimerModeCountdown && timer.Mode != models.TimerModeStopwatch {
+		return utils.NewApiError("Timer mode must be countdown or stopwatch", 0x0728).AsResponse(c)
+	}
+	if timer.Mode == models.TimerModeCountdown && timer.Duration <= 0 {
 		return utils.NewApiError("Timer duration must be positive", 0x0724).AsResponse(c)
 	}
+	if timer.Visibility == "" {
+		timer.Visibility = models.TimerVisibilityPublic
+	}
+	if !timer.Visibility.Valid() {
+		return utils.NewApiError("Timer visibility must be public or host", 0x072A).AsResponse(c)
+	}
+	if _, err := timer.Actions(); err != nil {
+		return utils.NewApiError("Invalid timer actions: "+err.Error(), 0x0727).AsResponse(c)
+	}
 
 	// Set created_by to current player
 	timer.CreatedBy = &player.ID
 
-	// Automatically activate the timer when created
+	// Automatically activate the timer when created, stopwatches count up without an expiry
 	timer.IsActive = true
 	now := time.Now()
 	timer.StartsAt = &now
-	expiresAt := now.Add(time.Duration(timer.Duration) * time.Second)
-	timer.ExpiresAt = &expiresAt
+	timer.ExpiresAt = nil
+	if timer.Mode == models.TimerModeCountdown {
+		expiresAt := now.Add(time.Duration(timer.Duration) * time.Second)
+		timer.ExpiresAt = &expiresAt
+	}
 
 	// If no show_id provided, use latest show
 	if timer.ShowID == nil {
@@ -64,6 +89,9 @@ func CreateTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Failed to create timer", 0x0726).AsResponse(c)
 	}
 
+	audit.Record(c, audit.TimerCreate, "timer", timer.ID, nil, timer)
+	broadcastTimerEvent("timer.created", &timer)
+
 	return c.Status(fiber.StatusCreated).JSON(timer)
 }
 
@@ -95,11 +123,14 @@ func UpdateTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Timer not found", 0x0734).AsResponse(c)
 	}
 
-	// Check if user owns this timer
-	if existingTimer.CreatedBy == nil || *existingTimer.CreatedBy != player.ID {
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
 		return utils.NewApiError("Access denied", 0x0735).AsResponse(c)
 	}
 
+	previousVisibility := existingTimer.Visibility
+	before := *existingTimer
+
 	// Update fields (only allow certain fields to be updated)
 	if updateData.Title != "" {
 		existingTimer.Title = updateData.Title
@@ -107,7 +138,16 @@ func UpdateTimer(c *fiber.Ctx) error {
 	if updateData.Duration > 0 {
 		existingTimer.Duration = updateData.Duration
 	}
+	if updateData.Visibility != "" {
+		if !updateData.Visibility.Valid() {
+			return utils.NewApiError("Timer visibility must be public or host", 0x0738).AsResponse(c)
+		}
+		existingTimer.Visibility = updateData.Visibility
+	}
 	if updateData.Settings != nil {
+		if _, err := updateData.Actions(); err != nil {
+			return utils.NewApiError("Invalid timer actions: "+err.Error(), 0x0737).AsResponse(c)
+		}
 		existingTimer.Settings = updateData.Settings
 	}
 
@@ -117,6 +157,21 @@ func UpdateTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Failed to update timer", 0x0736).AsResponse(c)
 	}
 
+	audit.Record(c, audit.TimerUpdate, "timer", existingTimer.ID, before, existingTimer)
+
+	// A timer switched to host-only still needs to disappear from clients who can no longer see it
+	broadcastTimerEvent("timer.updated", existingTimer)
+	if previousVisibility != existingTimer.Visibility && !existingTimer.IsPublic() {
+		if chatHub := sse.GetChatHub(); chatHub != nil {
+			chatHub.BroadcastEvent("timer.stopped", existingTimer)
+		}
+		if hostHub := sse.GetHostHub(); hostHub != nil {
+			hostHub.BroadcastEventWhere("timer.stopped", existingTimer, func(c *sse.Client) bool {
+				return !existingTimer.VisibleTo(c.Player)
+			})
+		}
+	}
+
 	return c.JSON(existingTimer)
 }
 
@@ -136,15 +191,15 @@ func DeleteTimer(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	// Get existing timer to check ownership
+	// Get existing timer to check access
 	existingTimer, err := db.GetTimerByID(ctx, timerID)
 	if err != nil {
 		log.Printf("failed to get timer %s: %v", timerID, err)
 		return utils.NewApiError("Timer not found", 0x0743).AsResponse(c)
 	}
 
-	// Check if user owns this timer
-	if existingTimer.CreatedBy == nil || *existingTimer.CreatedBy != player.ID {
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
 		return utils.NewApiError("Access denied", 0x0744).AsResponse(c)
 	}
 
@@ -154,5 +209,10 @@ func DeleteTimer(c *fiber.Ctx) error {
 		return utils.NewApiError("Failed to delete timer", 0x0745).AsResponse(c)
 	}
 
+	audit.Record(c, audit.TimerDelete, "timer", timerID, existingTimer, nil)
+
+	existingTimer.IsActive = false
+	broadcastTimerEvent("timer.stopped", existingTimer)
+
 	return c.JSON(fiber.Map{"success": true})
 }
@@ -0,0 +1,21 @@
+package timers
+
+import (
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/sse"
+)
+
+// broadcastTimerEvent tells host clients, and chat clients for public timers, about a timer lifecycle change
+func broadcastTimerEvent(opcode string, timer *models.Timer) {
+	sse.BroadcastTimerEvent(opcode, timer, timer)
+}
+
+// broadcastTimerLap tells clients that a lap was recorded on a stopwatch
+func broadcastTimerLap(timer *models.Timer, lap *models.TimerLap) {
+	sse.BroadcastTimerEvent("timer.lap", timer, lap)
+}
+
+// broadcastTimerResult tells clients the final value of a stopped stopwatch
+func broadcastTimerResult(timer *models.Timer, result *models.TimerResult) {
+	sse.BroadcastTimerEvent("timer.result", timer, result)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 	"wanshow-bingo/db"
 	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
 	"wanshow-bingo/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -59,7 +60,7 @@ func GetTimers(c *fiber.Ctx) error {
 	var countArgs []interface{}
 
 	baseQuery := `
-		SELECT id, title, duration, created_by, show_id, starts_at, expires_at, is_active, settings, created_at, updated_at, deleted_at
+		SELECT id, title, mode, visibility, duration, created_by, show_id, starts_at, expires_at, is_active, settings, paused_at, remaining_ms, created_at, updated_at, deleted_at
 		FROM timers
 		WHERE deleted_at IS NULL
 	`
@@ -75,6 +76,13 @@ func GetTimers(c *fiber.Ctx) error {
 		countArgs = append(countArgs, showID)
 	}
 
+	// Host-only timers are only listed for players who can create timers
+	player, _ := middleware.GetPlayerFromContext(c)
+	if player == nil || !player.CanCreateTimers() {
+		conditions = append(conditions, "visibility = 'public'")
+		countConditions = append(countConditions, "visibility = 'public'")
+	}
+
 	if isActive != "" {
 		active := isActive == "true"
 		conditions = append(conditions, "is_active = $"+strconv.Itoa(len(args)+1))
@@ -158,5 +166,10 @@ func GetTimerByID(c *fiber.Ctx) error {
 		return utils.NewApiError("Timer not found", 0x0712).AsResponse(c)
 	}
 
+	player, _ := middleware.GetPlayerFromContext(c)
+	if !timer.VisibleTo(player) {
+		return utils.NewApiError("Timer not found", 0x0713).AsResponse(c)
+	}
+
 	return c.JSON(timer)
 }
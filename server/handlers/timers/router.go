@@ -12,8 +12,10 @@ func init() {
 }
 
 func BuildRouter(router fiber.Router) {
-	router.Get("/", GetTimers)
-	router.Get("/:id", GetTimerByID)
+	router.Get("/", middleware.OptionalPlayerAuthMiddleware, GetTimers)
+	router.Get("/results", middleware.OptionalPlayerAuthMiddleware, GetTimerResults)
+	router.Get("/:id", middleware.OptionalPlayerAuthMiddleware, GetTimerByID)
+	router.Get("/:id/laps", middleware.OptionalPlayerAuthMiddleware, GetTimerLaps)
 
 	// Protected routes that require authentication
 	auth := router.Group("", middleware.AuthMiddleware)
@@ -23,4 +25,8 @@ func BuildRouter(router fiber.Router) {
 	auth.Post("/:id/start", StartTimer)
 	auth.Post("/:id/stop", StopTimer)
 	auth.Post("/:id/reset", ResetTimer)
+	auth.Post("/:id/pause", PauseTimer)
+	auth.Post("/:id/resume", ResumeTimer)
+	auth.Post("/:id/extend", ExtendTimer)
+	auth.Post("/:id/lap", LapTimer)
 }
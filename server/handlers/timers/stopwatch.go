@@ -0,0 +1,139 @@
+package timers
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LapTimerRequest is the body of POST /timers/:id/lap
+type LapTimerRequest struct {
+	Label string `json:"label"`
+}
+
+// LapTimer records a lap marker on a running stopwatch
+func LapTimer(c *fiber.Ctx) error {
+	timerID := c.Params("id")
+	if timerID == "" {
+		return utils.NewApiError("Timer ID is required", 0x07B1).AsResponse(c)
+	}
+
+	// Get authenticated player
+	player, ok := c.Locals("player").(*models.Player)
+	if !ok {
+		return utils.NewApiError("Authentication required", 0x07B2).AsResponse(c)
+	}
+
+	var req LapTimerRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.NewApiError("Invalid request body", 0x07B3).AsResponse(c)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Get existing timer to check access
+	existingTimer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get timer %s: %v", timerID, err)
+		return utils.NewApiError("Timer not found", 0x07B4).AsResponse(c)
+	}
+
+	// Check if user owns this timer or may manage any timer
+	if !canManageTimer(player, existingTimer) {
+		return utils.NewApiError("Access denied", 0x07B5).AsResponse(c)
+	}
+
+	if existingTimer.Mode != models.TimerModeStopwatch || !existingTimer.IsActive || existingTimer.StartsAt == nil {
+		return utils.NewApiError("Laps can only be recorded on a running stopwatch", 0x07B6).AsResponse(c)
+	}
+
+	lap := &models.TimerLap{
+		TimerID:    existingTimer.ID,
+		ShowID:     existingTimer.ShowID,
+		ElapsedMs:  time.Since(*existingTimer.StartsAt).Milliseconds(),
+		RecordedBy: &player.ID,
+	}
+	if req.Label != "" {
+		lap.Label = &req.Label
+	}
+
+	err = db.RecordTimerLap(ctx, lap)
+	if err != nil {
+		log.Printf("failed to record lap: %v", err)
+		return utils.NewApiError("Failed to record lap", 0x07B7).AsResponse(c)
+	}
+
+	broadcastTimerLap(existingTimer, lap)
+
+	return c.Status(fiber.StatusCreated).JSON(lap)
+}
+
+// GetTimerLaps retrieves the lap markers of a stopwatch
+func GetTimerLaps(c *fiber.Ctx) error {
+	timerID := c.Params("id")
+	if timerID == "" {
+		return utils.NewApiError("Timer ID is required", 0x07C1).AsResponse(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	timer, err := db.GetTimerByID(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get timer %s: %v", timerID, err)
+		return utils.NewApiError("Timer not found", 0x07C3).AsResponse(c)
+	}
+
+	player, _ := middleware.GetPlayerFromContext(c)
+	if !timer.VisibleTo(player) {
+		return utils.NewApiError("Timer not found", 0x07C4).AsResponse(c)
+	}
+
+	laps, err := db.GetTimerLaps(ctx, timerID)
+	if err != nil {
+		log.Printf("failed to get laps for timer %s: %v", timerID, err)
+		return utils.NewApiError("Failed to get laps", 0x07C2).AsResponse(c)
+	}
+
+	return c.JSON(fiber.Map{"laps": laps})
+}
+
+// GetTimerResults retrieves the final stopwatch values recorded for a show, defaulting to the latest show
+func GetTimerResults(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	showID := c.Query("show_id")
+	if showID == "" {
+		latestShow, err := db.GetLatestShow(ctx)
+		if err != nil {
+			log.Printf("failed to get latest show: %v", err)
+			return utils.NewApiError("Failed to get latest show", 0x07D1).AsResponse(c)
+		}
+		showID = latestShow.ID
+	}
+
+	// Results of host-only timers are only listed for players who can create timers
+	player, _ := middleware.GetPlayerFromContext(c)
+	includeHost := player != nil && player.CanCreateTimers()
+
+	results, err := db.GetTimerResultsForShow(ctx, showID, includeHost)
+	if err != nil {
+		log.Printf("failed to get timer results for show %s: %v", showID, err)
+		return utils.NewApiError("Failed to get timer results", 0x07D2).AsResponse(c)
+	}
+
+	return c.JSON(fiber.Map{
+		"show_id": showID,
+		"results": results,
+	})
+}
@@ -0,0 +1,75 @@
+package me
+
+import (
+	"wanshow-bingo/db"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetBlocks lists the IDs of the players the current player has blocked
+func GetBlocks(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(utils.NewApiError("Not authenticated", 401))
+	}
+
+	blocked, err := db.GetBlockedPlayerIDs(c.Context(), player.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to get blocked players", 500))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"blocked": blocked,
+	})
+}
+
+// PutBlock blocks a player by ID or display name
+func PutBlock(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(utils.NewApiError("Not authenticated", 401))
+	}
+
+	target, err := db.GetPlayerByIdentifier(c.Context(), c.Params("identifier"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(utils.NewApiError("Player not found", 404))
+	}
+
+	if target.ID == player.ID {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.NewApiError("You cannot block yourself", 400))
+	}
+
+	if err := db.BlockPlayer(c.Context(), player.ID, target.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to block player", 500))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"blocked": target.ID,
+	})
+}
+
+// DeleteBlock unblocks a player by ID or display name
+func DeleteBlock(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(utils.NewApiError("Not authenticated", 401))
+	}
+
+	target, err := db.GetPlayerByIdentifier(c.Context(), c.Params("identifier"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(utils.NewApiError("Player not found", 404))
+	}
+
+	if err := db.UnblockPlayer(c.Context(), player.ID, target.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.NewApiError("Failed to unblock player", 500))
+	}
+
+	return c.JSON(fiber.Map{
+		"success":   true,
+		"unblocked": target.ID,
+	})
+}
@@ -0,0 +1,29 @@
+package users
+
+import (
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/handlers/moderation"
+	"wanshow-bingo/middleware"
+	"wanshow-bingo/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReportPlayer reports a player, by ID or display name, to the moderators
+func ReportPlayer(c *fiber.Ctx) error {
+	player, err := middleware.GetPlayerFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(utils.NewApiError("Not authenticated", 401))
+	}
+
+	target, err := db.GetPlayerByIdentifier(c.Context(), c.Params("identifier"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(utils.NewApiError("Player not found", 404))
+	}
+
+	return moderation.FileReport(c, &models.Report{
+		ReporterID: player.ID,
+		PlayerID:   target.ID,
+	}, nil)
+}
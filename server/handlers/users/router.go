@@ -19,6 +19,10 @@ func BuildRouter(router fiber.Router) {
 
 	protected.Get("/me", me.Get)
 	protected.Put("/me", me.Put)
+	protected.Get("/me/blocks", me.GetBlocks)
+	protected.Put("/me/blocks/:identifier", me.PutBlock)
+	protected.Delete("/me/blocks/:identifier", me.DeleteBlock)
+	protected.Post("/:identifier/report", ReportPlayer)
 
 	// Public routes - no authentication required
 	router.Get("/", GetAll)
@@ -3,11 +3,12 @@ package main
 import (
 	"os"
 	_ "time/tzdata"
+	"wanshow-bingo/automod"
 	"wanshow-bingo/db"
 	_ "wanshow-bingo/handlers"
 	"wanshow-bingo/middleware"
 	_ "wanshow-bingo/sse"
-	_ "wanshow-bingo/timers"
+	"wanshow-bingo/timers"
 	"wanshow-bingo/utils"
 	"wanshow-bingo/whenplane/socket"
 )
@@ -16,6 +17,12 @@ func init() {
 	// Initialize optional database pool.
 	db.Init()
 
+	// Schedule the active timers stored in the database.
+	timers.Init()
+
+	// Load the moderation rules stored in the database.
+	automod.Init()
+
 	// Initialize the whenplane socket aggregator.
 	socket.Init()
 
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"log"
+	"sync"
 	"time"
 	"wanshow-bingo/avatar"
 	"wanshow-bingo/db"
@@ -30,6 +31,10 @@ type Client struct {
 	ticker          *time.Ticker
 	IsAuthenticated bool
 	Player          *models.Player
+
+	done       chan struct{}
+	doneOnce   sync.Once
+	doneReason string
 }
 
 func NewClient() *Client {
@@ -43,6 +48,7 @@ func NewClient() *Client {
 		Id:              id,
 		Queue:           make(chan string, 10),
 		IsAuthenticated: false,
+		done:            make(chan struct{}),
 	}
 }
 
@@ -56,6 +62,19 @@ func (c *Client) Bind(ctx *fiber.Ctx) error {
 		}
 	}
 
+	// Kicked and banned players may not reconnect until their sanction ends
+	if c.Player != nil {
+		sanction, err := db.GetActiveSanction(context.Background(), c.Player.ID, models.SanctionScopeChat, models.SanctionKick, models.SanctionBan)
+		if err != nil {
+			log.Printf("[SSE ClientChannel] - Failed to check sanctions for %s - %v", c.Player.ID, err)
+		} else if sanction != nil {
+			return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":    "You have been removed from chat",
+				"sanction": sanction,
+			})
+		}
+	}
+
 	// Set required headers for SSE on the RESPONSE
 	ctx.Set("Content-Type", "text/event-stream; charset=utf-8")
 	ctx.Set("Cache-Control", "no-cache")
@@ -93,12 +112,21 @@ func (c *Client) streamWriter(w *bufio.Writer) {
 
 		if c.Hub.name == "CHAT" {
 			go SendChatHistory(c)
+			go SendChatSettings(c)
 		}
+
+		go SendActiveTimers(c)
 	}
 
 	// Listen for messages and keep-alive ticks
 	for {
 		select {
+		case <-c.done:
+			disconnectEvent := BuildEvent("hub.disconnected", fiber.Map{
+				"reason": c.doneReason,
+			})
+			_ = c.write(disconnectEvent.String())
+			return
 		case message, ok := <-c.Queue:
 			if !ok {
 				return
@@ -128,6 +156,14 @@ func (c *Client) Read() <-chan string {
 	return c.Queue
 }
 
+// Disconnect ends the client's stream, telling it why before closing
+func (c *Client) Disconnect(reason string) {
+	c.doneOnce.Do(func() {
+		c.doneReason = reason
+		close(c.done)
+	})
+}
+
 func (c *Client) Stop() {
 	if c.Hub != nil {
 		c.Hub.UnregisterClient(c)
@@ -192,8 +228,33 @@ func (c *Client) GetCapabilities() ClientCapabilities {
 	return UnauthorizedCapabilities
 }
 
+// SendChatSettings tells a newly connected chat client about the latest show's slow mode
+// and account age requirement
+func SendChatSettings(c *Client) {
+	ctx := context.Background()
+
+	show, err := db.GetLatestShow(ctx)
+	if err != nil {
+		return
+	}
+
+	settings, err := db.GetChatSettings(ctx, show.ID)
+	if err != nil {
+		log.Printf("[SSE ClientChannel] - Failed to retrieve chat settings - %v", err)
+		return
+	}
+
+	settingsEvent := BuildEvent("chat.settings", settings)
+	c.Send(settingsEvent.String())
+}
+
 func SendChatHistory(c *Client) {
-	history, err := db.GetMessageHistory(context.Background())
+	// Shadow muted players see their own messages as if nothing had happened
+	viewerID := ""
+	if c.Player != nil {
+		viewerID = c.Player.ID
+	}
+	history, err := db.GetMessageHistory(context.Background(), viewerID)
 
 	if err != nil {
 		log.Printf("[SSE ClientChannel] - Failed to retrieve chat history - %v", err)
@@ -222,6 +283,21 @@ func SendChatHistory(c *Client) {
 		history[i], history[j] = history[j], history[i]
 	}
 
+	// Index the history so replies can show a preview of their parent
+	messageMap := make(map[string]*models.Message, len(history))
+	for i := range history {
+		messageMap[history[i].ID] = &history[i]
+	}
+
+	messageIDs := make([]string, 0, len(history))
+	for _, msg := range history {
+		messageIDs = append(messageIDs, msg.ID)
+	}
+	reactions, err := db.GetReactionCounts(context.Background(), messageIDs, viewerID)
+	if err != nil {
+		log.Printf("[SSE ClientChannel] - Failed to retrieve reactions - %v", err)
+	}
+
 	// Send chat history
 	for _, msg := range history {
 		// Attach player info to message
@@ -232,11 +308,27 @@ func SendChatHistory(c *Client) {
 			"contents":   msg.Contents,
 			"system":     msg.System,
 			"replying":   msg.Replying,
+			"mentions":   msg.MentionIDs(),
 			"created_at": msg.CreatedAt,
 			"updated_at": msg.UpdatedAt,
 			"deleted_at": msg.DeletedAt,
+			"edited_at":  msg.EditedAt,
+			"reactions":  reactions[msg.ID],
 			"player":     playerMap[msg.PlayerID],
 		}
+		if msg.Kind != nil {
+			messageWithPlayer["kind"] = msg.Kind
+			messageWithPlayer["data"] = msg.Data
+		}
+		if msg.Replying != nil {
+			parent, ok := messageMap[*msg.Replying]
+			if !ok {
+				parent, _ = db.GetMessageByID(context.Background(), *msg.Replying)
+			}
+			if parent != nil && parent.VisibleTo(viewerID) {
+				messageWithPlayer["parent"] = parent.Preview()
+			}
+		}
 		msgEvent := BuildEvent("chat.message", messageWithPlayer)
 		c.Send(msgEvent.String())
 	}
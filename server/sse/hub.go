@@ -3,6 +3,7 @@ package sse
 import (
 	"encoding/json"
 	"log"
+	"slices"
 	"wanshow-bingo/avatar"
 	"wanshow-bingo/utils"
 
@@ -60,6 +61,20 @@ type Hub struct {
 	register   chan *Client
 	unregister chan *Client
 	broadcast  chan string
+	filtered   chan filteredMessage
+	disconnect chan directMessage
+}
+
+// directMessage is a message delivered only to the clients of specific players
+type directMessage struct {
+	playerIDs []string
+	msg       string
+}
+
+// filteredMessage is a message delivered only to the clients accept returns true for
+type filteredMessage struct {
+	accept func(c *Client) bool
+	msg    string
 }
 
 func NewHub(name string) *Hub {
@@ -69,6 +84,8 @@ func NewHub(name string) *Hub {
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan string, 256),
+		filtered:   make(chan filteredMessage, 256),
+		disconnect: make(chan directMessage, 16),
 	}
 }
 
@@ -110,6 +127,28 @@ func (h *Hub) Run() {
 					}
 				}
 			}
+		case fm := <-h.filtered:
+			for c, client := range h.clients {
+				if !fm.accept(client) {
+					continue
+				}
+
+				select {
+				case client.Queue <- fm.msg:
+					utils.Debugf("[SSE - %s] (0x05) Sent filtered message to client %s", h.name, c)
+				default:
+					go h.UnregisterClient(client)
+				}
+			}
+		case dm := <-h.disconnect:
+			for c, client := range h.clients {
+				if client.Player == nil || !slices.Contains(dm.playerIDs, client.Player.ID) {
+					continue
+				}
+
+				utils.Debugf("[SSE - %s] Disconnecting client %s", h.name, c)
+				client.Disconnect(dm.msg)
+			}
 		}
 	}
 }
@@ -126,6 +165,27 @@ func (h *Hub) Broadcast(msg string) {
 	h.broadcast <- msg
 }
 
+// SendEventToPlayers sends an event only to the connected clients of the given players
+func (h *Hub) SendEventToPlayers(eventName string, data any, playerIDs ...string) {
+	utils.Debugf("[SSE - %s] Sending event %s to players %v", h.name, eventName, playerIDs)
+	h.BroadcastEventWhere(eventName, data, func(c *Client) bool {
+		return c.Player != nil && slices.Contains(playerIDs, c.Player.ID)
+	})
+}
+
+// BroadcastEventWhere sends an event only to the connected clients accept returns true for.
+// accept runs on the hub's goroutine and must not block.
+func (h *Hub) BroadcastEventWhere(eventName string, data any, accept func(c *Client) bool) {
+	event := BuildEvent(eventName, data)
+	utils.Debugf("[SSE - %s] Building filtered event: %+v", h.name, event)
+	h.filtered <- filteredMessage{accept: accept, msg: event.String()}
+}
+
+// DisconnectPlayer closes every connected client of a player, sending the reason first
+func (h *Hub) DisconnectPlayer(playerID string, reason string) {
+	h.disconnect <- directMessage{playerIDs: []string{playerID}, msg: reason}
+}
+
 func (h *Hub) BroadcastConnectionCount() {
 	h.Broadcast(h.BuildConnectionCount())
 }
@@ -0,0 +1,81 @@
+package sse
+
+import (
+	"sync"
+	"time"
+)
+
+// reactionFlushInterval is how often queued reaction changes are broadcast
+const reactionFlushInterval = 500 * time.Millisecond
+
+// ReactionDelta is the net change of one emoji's count on one message
+type ReactionDelta struct {
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
+	Delta     int    `json:"delta"`
+}
+
+type reactionKey struct {
+	messageID string
+	emoji     string
+}
+
+// reactionCoalescer merges reaction changes made within a flush interval, so a burst
+// of reactions becomes a single chat.reaction event per interval
+type reactionCoalescer struct {
+	mu      sync.Mutex
+	pending map[reactionKey]int
+	order   []reactionKey
+}
+
+var reactions = &reactionCoalescer{pending: make(map[reactionKey]int)}
+
+func init() {
+	go func() {
+		ticker := time.NewTicker(reactionFlushInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			deltas := reactions.drain()
+			if len(deltas) > 0 && chatHub != nil {
+				chatHub.BroadcastEvent("chat.reaction", map[string]interface{}{
+					"reactions": deltas,
+				})
+			}
+		}
+	}()
+}
+
+// QueueReactionDelta records a change to a message's reaction count, to be broadcast with
+// the other changes made in the same interval
+func QueueReactionDelta(messageID string, emoji string, delta int) {
+	reactions.add(messageID, emoji, delta)
+}
+
+func (r *reactionCoalescer) add(messageID string, emoji string, delta int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := reactionKey{messageID: messageID, emoji: emoji}
+	if _, ok := r.pending[key]; !ok {
+		r.order = append(r.order, key)
+	}
+	r.pending[key] += delta
+}
+
+// drain returns the net changes queued since the last drain, leaving out those which cancelled out
+func (r *reactionCoalescer) drain() []ReactionDelta {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deltas []ReactionDelta
+	for _, key := range r.order {
+		if delta := r.pending[key]; delta != 0 {
+			deltas = append(deltas, ReactionDelta{MessageID: key.messageID, Emoji: key.emoji, Delta: delta})
+		}
+	}
+
+	r.pending = make(map[reactionKey]int)
+	r.order = nil
+	return deltas
+}
@@ -0,0 +1,59 @@
+package sse
+
+import (
+	"context"
+	"log"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+)
+
+// BroadcastTimerEvent sends a timer event to the host hub clients who may see the timer, and to
+// the chat hub when the timer is public
+func BroadcastTimerEvent(opcode string, timer *models.Timer, data any) {
+	if hostHub != nil {
+		if timer.IsPublic() {
+			hostHub.BroadcastEvent(opcode, data)
+		} else {
+			hostHub.BroadcastEventWhere(opcode, data, func(c *Client) bool {
+				return timer.VisibleTo(c.Player)
+			})
+		}
+	}
+	if timer.IsPublic() && chatHub != nil {
+		chatHub.BroadcastEvent(opcode, data)
+	}
+}
+
+// SendActiveTimers sends the active timers of the latest show to a newly connected client.
+// Chat clients only receive public timers, host clients the timers they may see.
+func SendActiveTimers(c *Client) {
+	ctx := context.Background()
+
+	show, err := db.GetLatestShow(ctx)
+	if err != nil || show == nil {
+		return
+	}
+
+	timers, err := db.GetActiveTimersForShow(ctx, show.ID)
+	if err != nil {
+		log.Printf("[SSE ClientChannel] - Failed to retrieve active timers - %v", err)
+		return
+	}
+
+	visible := make([]models.Timer, 0, len(timers))
+	for _, timer := range timers {
+		if c.Hub != nil && c.Hub.name == "CHAT" && !timer.IsPublic() {
+			continue
+		}
+		if !timer.VisibleTo(c.Player) {
+			continue
+		}
+		visible = append(visible, timer)
+	}
+
+	snapshotEvent := BuildEvent("timer.snapshot", map[string]interface{}{
+		"show_id": show.ID,
+		"timers":  visible,
+	})
+	c.Send(snapshotEvent.String())
+}
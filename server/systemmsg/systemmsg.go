@@ -0,0 +1,159 @@
+package systemmsg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/sse"
+)
+
+// defaultLocale is the locale templates fall back to when a locale or a kind is missing
+const defaultLocale = "en"
+
+// Locale is the locale system messages are stored in, set with SYSTEM_MESSAGE_LOCALE
+var Locale = func() string {
+	if locale := os.Getenv("SYSTEM_MESSAGE_LOCALE"); locale != "" {
+		return locale
+	}
+	return defaultLocale
+}()
+
+// templateSource holds the text of each template by locale and kind. Templates use text/template
+// with the message data as the dot, and write markdown.
+var templateSource = map[string]map[models.SystemMessageKind]string{
+	defaultLocale: {
+		models.SystemMessageTileConfirmed: "**TILE CONFIRMED**{{if .context}}\n\n{{.tile_title}}\n\n> Context: {{.context}}{{else}} {{.tile_title}}{{end}}",
+		models.SystemMessageWinner:        "**BINGO WINNER!** {{.player_name}} has won the bingo game!",
+		models.SystemMessageTimerExpired:  "{{.message}}",
+		models.SystemMessageAnnouncement:  "{{.text}}",
+	},
+}
+
+var (
+	templatesMu sync.RWMutex
+	templates   = make(map[string]map[models.SystemMessageKind]*template.Template)
+)
+
+func init() {
+	for locale, source := range templateSource {
+		if err := RegisterLocale(locale, source); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterLocale adds the templates of a locale, replacing any it already had. Kinds the locale
+// leaves out are rendered with the default locale.
+func RegisterLocale(locale string, source map[models.SystemMessageKind]string) error {
+	parsed := make(map[models.SystemMessageKind]*template.Template, len(source))
+	for kind, text := range source {
+		tmpl, err := template.New(locale + "." + string(kind)).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return fmt.Errorf("system message template %s for %s: %w", kind, locale, err)
+		}
+		parsed[kind] = tmpl
+	}
+
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates[locale] = parsed
+	return nil
+}
+
+// Render writes the text of a system message in a locale
+func Render(locale string, kind models.SystemMessageKind, data map[string]interface{}) (string, error) {
+	templatesMu.RLock()
+	tmpl, ok := templates[locale][kind]
+	if !ok {
+		tmpl, ok = templates[defaultLocale][kind]
+	}
+	templatesMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown system message kind %q", kind)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Post renders a system message in Locale, saves it to a show's chat and broadcasts it. playerID
+// is who the message is attributed to, SYSTEM for automatic messages.
+func Post(ctx context.Context, showID string, playerID string, kind models.SystemMessageKind, data map[string]interface{}) (*models.Message, error) {
+	contents, err := Render(Locale, kind, data)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &models.Message{
+		ShowID:    showID,
+		PlayerID:  playerID,
+		Contents:  contents,
+		System:    true,
+		Kind:      &kind,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := db.PersistMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	if chatHub := sse.GetChatHub(); chatHub != nil {
+		chatHub.BroadcastEvent("chat.message", message)
+	}
+
+	return message, nil
+}
+
+// TileConfirmed is the data of a tile_confirmed message. confirmedBy is the host, or empty when a
+// timer confirmed the tile, in which case timerID names it.
+func TileConfirmed(tile *models.Tile, confirmedBy string, timerID string, confirmationContext string) map[string]interface{} {
+	data := map[string]interface{}{
+		"tile_id":    tile.ID,
+		"tile_title": tile.Title,
+	}
+	if confirmedBy != "" {
+		data["confirmed_by"] = confirmedBy
+	}
+	if timerID != "" {
+		data["timer_id"] = timerID
+	}
+	if confirmationContext != "" {
+		data["context"] = confirmationContext
+	}
+	return data
+}
+
+// Winner is the data of a winner message
+func Winner(player *models.Player, boardID string) map[string]interface{} {
+	return map[string]interface{}{
+		"player_id":   player.ID,
+		"player_name": player.DisplayName,
+		"board_id":    boardID,
+	}
+}
+
+// TimerExpired is the data of a timer_expired message
+func TimerExpired(timer *models.Timer, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"timer_id":    timer.ID,
+		"timer_title": timer.Title,
+		"message":     message,
+	}
+}
+
+// Announcement is the data of an announcement
+func Announcement(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"text": text,
+	}
+}
@@ -0,0 +1,68 @@
+package systemmsg
+
+import (
+	"testing"
+	"wanshow-bingo/db/models"
+)
+
+func TestRender(t *testing.T) {
+	tile := &models.Tile{ID: "tile_001", Title: "Linus drops something"}
+	player := &models.Player{ID: "usr_001", DisplayName: "LinusTech#1337"}
+	timer := &models.Timer{ID: "tmr_001", Title: "Sponsor spot"}
+
+	tests := []struct {
+		name     string
+		kind     models.SystemMessageKind
+		data     map[string]interface{}
+		expected string
+	}{
+		{"Tile confirmed", models.SystemMessageTileConfirmed, TileConfirmed(tile, "usr_002", "", ""),
+			"**TILE CONFIRMED** Linus drops something"},
+		{"Tile confirmed with context", models.SystemMessageTileConfirmed, TileConfirmed(tile, "usr_002", "", "At 1:02:03"),
+			"**TILE CONFIRMED**\n\nLinus drops something\n\n> Context: At 1:02:03"},
+		{"Winner", models.SystemMessageWinner, Winner(player, "brd_001"),
+			"**BINGO WINNER!** LinusTech#1337 has won the bingo game!"},
+		{"Timer expired", models.SystemMessageTimerExpired, TimerExpired(timer, "Sponsor spot is over"),
+			"Sponsor spot is over"},
+		{"Announcement", models.SystemMessageAnnouncement, Announcement("Welcome to the show"),
+			"Welcome to the show"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render(defaultLocale, tt.kind, tt.data)
+			if err != nil {
+				t.Fatalf("Render returned error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderLocaleFallback(t *testing.T) {
+	err := RegisterLocale("de", map[models.SystemMessageKind]string{
+		models.SystemMessageWinner: "**BINGO!** {{.player_name}} hat gewonnen!",
+	})
+	if err != nil {
+		t.Fatalf("RegisterLocale returned error: %v", err)
+	}
+
+	player := &models.Player{ID: "usr_001", DisplayName: "Luke"}
+	if result, _ := Render("de", models.SystemMessageWinner, Winner(player, "brd_001")); result != "**BINGO!** Luke hat gewonnen!" {
+		t.Errorf("Render(de, winner) = %q", result)
+	}
+
+	// Kinds and locales without a template use the default locale
+	if result, _ := Render("de", models.SystemMessageAnnouncement, Announcement("Hallo")); result != "Hallo" {
+		t.Errorf("Render(de, announcement) = %q", result)
+	}
+	if result, _ := Render("fr", models.SystemMessageWinner, Winner(player, "brd_001")); result != "**BINGO WINNER!** Luke has won the bingo game!" {
+		t.Errorf("Render(fr, winner) = %q", result)
+	}
+
+	if _, err := Render(defaultLocale, "unknown", nil); err == nil {
+		t.Error("Render of an unknown kind should fail")
+	}
+}
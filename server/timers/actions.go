@@ -0,0 +1,114 @@
+package timers
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanshow-bingo/db"
+	"wanshow-bingo/db/models"
+	"wanshow-bingo/sse"
+	"wanshow-bingo/systemmsg"
+	"wanshow-bingo/utils"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// runActions executes the expiry actions declared in the timer settings, in order.
+// A failing action is logged and does not prevent the remaining actions from running.
+func runActions(ctx context.Context, timer *models.Timer) {
+	actions, err := timer.Actions()
+	if err != nil {
+		log.Printf("Timer %s has invalid actions: %v", timer.ID, err)
+		return
+	}
+
+	for _, action := range actions {
+		switch action.Type {
+		case models.TimerActionConfirmTile:
+			confirmTile(ctx, timer, action)
+		case models.TimerActionSystemMessage:
+			if timer.ShowID != nil {
+				postSystemMessage(ctx, *timer.ShowID, models.SystemMessageTimerExpired, systemmsg.TimerExpired(timer, action.Message))
+			}
+		case models.TimerActionHostEvent:
+			broadcastHostEvent(timer, action)
+		}
+	}
+}
+
+// confirmTile confirms a tile for the timer's show, as long as that show is still live
+func confirmTile(ctx context.Context, timer *models.Timer, action models.TimerAction) {
+	if timer.ShowID == nil {
+		return
+	}
+
+	latestShow, err := db.GetLatestShow(ctx)
+	if err != nil || latestShow.State != models.ShowStateLive || latestShow.ID != *timer.ShowID {
+		utils.Debugf("Skipping tile confirmation for timer %s: show is not live", timer.ID)
+		return
+	}
+
+	tile, err := db.GetTileByID(ctx, action.TileID)
+	if err != nil {
+		log.Printf("Failed to get tile %s for timer %s: %v", action.TileID, timer.ID, err)
+		return
+	}
+
+	id, _ := gonanoid.New(10)
+
+	confirmation := &models.TileConfirmation{
+		ID:               id,
+		ShowID:           latestShow.ID,
+		TileID:           tile.ID,
+		ConfirmedBy:      nil, // system
+		ConfirmationTime: time.Now(),
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+	if action.Context != "" {
+		confirmation.Context = &action.Context
+	}
+
+	err = db.PersistTileConfirmation(ctx, confirmation)
+	if err != nil {
+		log.Printf("Failed to confirm tile %s for timer %s: %v", tile.ID, timer.ID, err)
+		return
+	}
+
+	postSystemMessage(ctx, latestShow.ID, models.SystemMessageTileConfirmed, systemmsg.TileConfirmed(tile, "", timer.ID, action.Context))
+
+	// Broadcast tile confirmation to host hub
+	hostHub := sse.GetHostHub()
+	if hostHub != nil {
+		hostHub.BroadcastEvent("tile.confirm", map[string]interface{}{
+			"tileId": tile.ID,
+		})
+	}
+
+	log.Printf("Automatically confirmed tile %s for show %s", tile.ID, latestShow.ID)
+}
+
+// postSystemMessage saves a system message from SYSTEM and broadcasts it to the chat hub
+func postSystemMessage(ctx context.Context, showID string, kind models.SystemMessageKind, data map[string]interface{}) {
+	if _, err := systemmsg.Post(ctx, showID, "SYSTEM", kind, data); err != nil {
+		log.Printf("Failed to send timer system message: %v", err)
+	}
+}
+
+// broadcastHostEvent sends a custom event to the host hub
+func broadcastHostEvent(timer *models.Timer, action models.TimerAction) {
+	hostHub := sse.GetHostHub()
+	if hostHub == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"timer_id": timer.ID,
+		"show_id":  timer.ShowID,
+	}
+	for k, v := range action.Data {
+		data[k] = v
+	}
+
+	hostHub.BroadcastEvent(action.Event, data)
+}
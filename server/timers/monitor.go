@@ -3,136 +3,107 @@ package timers
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 	"wanshow-bingo/db"
-	"wanshow-bingo/db/models"
 	"wanshow-bingo/sse"
-
-	"github.com/google/uuid"
-	gonanoid "github.com/matoous/go-nanoid/v2"
-	"github.com/robfig/cron/v3"
 )
 
-var timerCron *cron.Cron
+// resyncInterval is how often the scheduler is reconciled with the database,
+// to pick up timers created or changed by other instances
+const resyncInterval = 5 * time.Minute
 
-func init() {
-	timerCron = cron.New()
-	timerCron.Start()
+var (
+	scheduler *Scheduler
+	stop      chan struct{}
+	stopOnce  sync.Once
+)
 
-	// Check for expired timers every 2 seconds
-	_, err := timerCron.AddFunc("@every 2s", checkExpiredTimers)
-	if err != nil {
-		log.Printf("Failed to schedule timer monitor: %v", err)
-	}
+func init() {
+	scheduler = NewScheduler(handleExpiredTimer)
+	stop = make(chan struct{})
+
+	// Keep the scheduler in sync with timer changes made through the db package
+	db.OnTimerScheduleChange(func(timerID string, expiresAt *time.Time) {
+		if expiresAt == nil {
+			scheduler.Cancel(timerID)
+		} else {
+			scheduler.Schedule(timerID, *expiresAt)
+		}
+	})
 
-	log.Println("Timer monitor initialized")
+	go scheduler.Run(stop)
 }
 
-// confirmWANTile automatically confirms the 4-hour WAN show tile
-func confirmWANTile(ctx context.Context, showID string) {
-	tileID := "BfaqFYztlR"
-	contextStr := "Automatic confirmation after 4-hour timer"
-
-	id, _ := gonanoid.New(10)
-
-	confirmation := &models.TileConfirmation{
-		ID:               id,
-		ShowID:           showID,
-		TileID:           tileID,
-		ConfirmedBy:      nil, // system
-		Context:          &contextStr,
-		ConfirmationTime: time.Now(),
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
-	}
+// Init rebuilds the scheduler from the database and starts the periodic resync.
+// It must be called after the database pool has been initialised.
+func Init() {
+	resync()
+
+	go func() {
+		ticker := time.NewTicker(resyncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				resync()
+			}
+		}
+	}()
 
-	err := db.PersistTileConfirmation(ctx, confirmation)
-	if err != nil {
-		log.Printf("Failed to confirm WAN tile: %v", err)
-		return
-	}
+	log.Println("Timer monitor initialized")
+}
 
-	// Create system message
-	messageContent := "**TILE CONFIRMED** 4 Hour WAN Show"
-	systemMessage := &models.Message{
-		ID:        uuid.New().String(),
-		ShowID:    showID,
-		PlayerID:  "SYSTEM",
-		Contents:  messageContent,
-		System:    true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+// resync schedules every active timer stored in the database
+func resync() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	err = db.PersistMessage(ctx, systemMessage)
+	timers, err := db.GetScheduledTimers(ctx)
 	if err != nil {
-		log.Printf("Failed to send WAN tile confirmation message: %v", err)
+		log.Printf("Failed to load scheduled timers: %v", err)
 		return
 	}
 
-	// Broadcast message to chat hub
-	chatHub := sse.GetChatHub()
-	if chatHub != nil {
-		chatHub.BroadcastEvent("chat.message", systemMessage)
+	for _, timer := range timers {
+		scheduler.Schedule(timer.ID, *timer.ExpiresAt)
 	}
-
-	// Broadcast tile confirmation to host hub
-	hostHub := sse.GetHostHub()
-	if hostHub != nil {
-		hostHub.BroadcastEvent("tile.confirm", map[string]interface{}{
-			"tileId": tileID,
-		})
-	}
-
-	log.Printf("Automatically confirmed WAN tile for show %s", showID)
 }
 
-// checkExpiredTimers checks for expired timers and sends SSE events
-func checkExpiredTimers() {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+// handleExpiredTimer runs the expiry actions for a timer and sends SSE events
+func handleExpiredTimer(timerID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	expiredTimers, err := db.GetExpiredTimers(ctx)
+	// Claim the timer so it only fires once, even with several instances running
+	timer, err := db.ClaimExpiredTimer(ctx, timerID, time.Now())
 	if err != nil {
-		log.Printf("Failed to get expired timers: %v", err)
+		log.Printf("Failed to claim expired timer %s: %v", timerID, err)
 		return
 	}
 
-	for _, timer := range expiredTimers {
-		// Special handling for WAN Show Timer
-		if timer.Title == "WAN Show Timer" && timer.ShowID != nil {
-			// Check if show is still live
-			latestShow, err := db.GetLatestShow(ctx)
-			if err == nil && latestShow.State == models.ShowStateLive && latestShow.ID == *timer.ShowID {
-				// Automatically confirm the 4-hour WAN show tile
-				confirmWANTile(ctx, latestShow.ID)
-			}
+	if timer == nil {
+		// Already handled elsewhere, or moved to a later expiry by another instance
+		current, err := db.GetTimerByID(ctx, timerID)
+		if err == nil && current.IsActive && current.ExpiresAt != nil && current.ExpiresAt.After(time.Now()) {
+			scheduler.Schedule(current.ID, *current.ExpiresAt)
 		}
+		return
+	}
 
-		// Send timer.expired event to the host SSE stream
-		hostHub := sse.GetHostHub()
-		if hostHub != nil {
-			hostHub.BroadcastEvent("timer.expired", map[string]interface{}{
-				"timer_id":   timer.ID,
-				"title":      timer.Title,
-				"show_id":    timer.ShowID,
-				"created_by": timer.CreatedBy,
-				"expired_at": timer.ExpiresAt,
-			})
-		}
+	// Execute the actions declared in the timer settings
+	runActions(ctx, timer)
 
-		// Stop the timer (deactivate it)
-		err := db.StopTimer(ctx, timer.ID)
-		if err != nil {
-			log.Printf("Failed to stop expired timer %s: %v", timer.ID, err)
-		}
-	}
+	// Send timer.expired to the host hub, and to chat for public timers
+	sse.BroadcastTimerEvent("timer.expired", timer, timer)
 }
 
 // Cleanup stops the timer monitor
 func Cleanup() {
-	if timerCron != nil {
-		ctx := timerCron.Stop()
-		<-ctx.Done()
-	}
+	stopOnce.Do(func() {
+		close(stop)
+	})
 }
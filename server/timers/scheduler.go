@@ -0,0 +1,155 @@
+package timers
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// scheduledTimer is a single timer waiting in the scheduler heap
+type scheduledTimer struct {
+	id    string
+	at    time.Time
+	index int
+}
+
+// timerHeap is a min-heap of scheduled timers ordered by expiry time
+type timerHeap []*scheduledTimer
+
+func (h timerHeap) Len() int           { return len(h) }
+func (h timerHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerHeap) Push(x any) {
+	entry := x.(*scheduledTimer)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *timerHeap) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*h = old[:n-1]
+	return entry
+}
+
+// Scheduler fires a callback for each timer at its exact expiry time.
+// Each timer ID is scheduled at most once; scheduling it again moves its expiry.
+type Scheduler struct {
+	mu      sync.Mutex
+	heap    timerHeap
+	entries map[string]*scheduledTimer
+	wake    chan struct{}
+	fire    func(timerID string)
+}
+
+// NewScheduler creates a scheduler which calls fire when a timer expires
+func NewScheduler(fire func(timerID string)) *Scheduler {
+	return &Scheduler{
+		entries: make(map[string]*scheduledTimer),
+		wake:    make(chan struct{}, 1),
+		fire:    fire,
+	}
+}
+
+// Schedule adds a timer or moves it to a new expiry time
+func (s *Scheduler) Schedule(timerID string, at time.Time) {
+	s.mu.Lock()
+	if entry, ok := s.entries[timerID]; ok {
+		entry.at = at
+		heap.Fix(&s.heap, entry.index)
+	} else {
+		entry := &scheduledTimer{id: timerID, at: at}
+		heap.Push(&s.heap, entry)
+		s.entries[timerID] = entry
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Cancel removes a timer from the scheduler, if present
+func (s *Scheduler) Cancel(timerID string) {
+	s.mu.Lock()
+	if entry, ok := s.entries[timerID]; ok {
+		heap.Remove(&s.heap, entry.index)
+		delete(s.entries, timerID)
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Len returns the number of timers waiting to fire
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.heap)
+}
+
+// notify wakes the run loop so it can recompute the next expiry
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// popDue removes and returns all timers which have expired by now, and the time
+// until the next timer is due (or -1 if the scheduler is empty)
+func (s *Scheduler) popDue(now time.Time) ([]string, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []string
+	for len(s.heap) > 0 && !s.heap[0].at.After(now) {
+		entry := heap.Pop(&s.heap).(*scheduledTimer)
+		delete(s.entries, entry.id)
+		due = append(due, entry.id)
+	}
+
+	if len(s.heap) == 0 {
+		return due, -1
+	}
+	return due, s.heap[0].at.Sub(now)
+}
+
+// Run fires timers as they expire until stop is closed
+func (s *Scheduler) Run(stop <-chan struct{}) {
+	clock := time.NewTimer(time.Hour)
+	defer clock.Stop()
+
+	for {
+		due, next := s.popDue(time.Now())
+		for _, id := range due {
+			go s.fire(id)
+		}
+
+		var wait <-chan time.Time
+		if next >= 0 {
+			clock.Reset(next)
+			wait = clock.C
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-s.wake:
+		case <-wait:
+		}
+
+		if !clock.Stop() {
+			select {
+			case <-clock.C:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package timers
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulerFiresInExpiryOrder(t *testing.T) {
+	var mu sync.Mutex
+	var fired []string
+	done := make(chan struct{}, 3)
+
+	s := NewScheduler(func(timerID string) {
+		mu.Lock()
+		fired = append(fired, timerID)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.Run(stop)
+
+	now := time.Now()
+	s.Schedule("c", now.Add(60*time.Millisecond))
+	s.Schedule("a", now.Add(20*time.Millisecond))
+	s.Schedule("b", now.Add(40*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for timers, fired %v", fired)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"a", "b", "c"}
+	for i, id := range expected {
+		if fired[i] != id {
+			t.Fatalf("fired %v, expected %v", fired, expected)
+		}
+	}
+}
+
+func TestSchedulerRescheduleAndCancel(t *testing.T) {
+	fired := make(chan string, 2)
+	s := NewScheduler(func(timerID string) {
+		fired <- timerID
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.Run(stop)
+
+	now := time.Now()
+	s.Schedule("moved", now.Add(20*time.Millisecond))
+	s.Schedule("cancelled", now.Add(20*time.Millisecond))
+	s.Schedule("moved", now.Add(80*time.Millisecond))
+	s.Cancel("cancelled")
+
+	if s.Len() != 1 {
+		t.Fatalf("Len() = %d, expected 1", s.Len())
+	}
+
+	select {
+	case id := <-fired:
+		if id != "moved" {
+			t.Fatalf("fired %q, expected %q", id, "moved")
+		}
+		if time.Since(now) < 80*time.Millisecond {
+			t.Fatalf("rescheduled timer fired early after %v", time.Since(now))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for rescheduled timer")
+	}
+
+	select {
+	case id := <-fired:
+		t.Fatalf("unexpected timer %q fired", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// llmToxicThreshold is the confidence above which a toxic verdict rejects a message
+const llmToxicThreshold = 0.7
+
+// LLMModerationRequest represents a request to the LLM moderation service
+type LLMModerationRequest struct {
+	Content string `json:"content"`
+}
+
+// LLMModerationResponse represents the response from the LLM moderation service
+type LLMModerationResponse struct {
+	Toxic      bool    `json:"toxic"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+// LLMModerationEnabled reports whether an LLM moderation endpoint is configured
+func LLMModerationEnabled() bool {
+	return os.Getenv("LLM_MODERATION_ENDPOINT") != ""
+}
+
+var llmClient = &http.Client{
+	Timeout: time.Duration(GetEnvInt("LLM_MODERATION_TIMEOUT_SECONDS", 5)) * time.Second,
+}
+
+// QueryLLMModeration asks the LLM moderation service for a verdict, using a cached verdict
+// for content it has already seen. Failed requests are returned as errors and not cached.
+func QueryLLMModeration(content string) (*LLMModerationResponse, error) {
+	llmEndpoint := os.Getenv("LLM_MODERATION_ENDPOINT")
+	if llmEndpoint == "" {
+		return nil, errors.New("LLM moderation endpoint not configured")
+	}
+
+	key := contentHash(content)
+	if cached, ok := llmVerdicts.get(key); ok {
+		return cached, nil
+	}
+
+	jsonData, err := json.Marshal(LLMModerationRequest{Content: content})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal LLM request: %w", err)
+	}
+
+	resp, err := llmClient.Post(llmEndpoint, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("LLM moderation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("LLM moderation returned status %d", resp.StatusCode)
+	}
+
+	var llmResp LLMModerationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&llmResp); err != nil {
+		return nil, fmt.Errorf("failed to decode LLM response: %w", err)
+	}
+
+	llmVerdicts.put(key, &llmResp)
+	return &llmResp, nil
+}
+
+// LLMVerdict turns an LLM moderation response into a moderation result
+func LLMVerdict(resp *LLMModerationResponse) *ModerationResult {
+	if resp.Toxic && resp.Confidence > llmToxicThreshold {
+		return reject("llm.toxic", fmt.Sprintf("LLM detected toxic content: %s", resp.Reason), "")
+	}
+	return &ModerationResult{Allowed: true}
+}
+
+// CheckLLMModeration performs LLM-based content moderation, allowing the content when the
+// service is not configured or cannot be reached
+func CheckLLMModeration(content string) *ModerationResult {
+	if !LLMModerationEnabled() {
+		return &ModerationResult{Allowed: true}
+	}
+
+	resp, err := QueryLLMModeration(content)
+	if err != nil {
+		Debugf("%v", err)
+		return &ModerationResult{Allowed: true} // Allow on error
+	}
+
+	return LLMVerdict(resp)
+}
+
+// contentHash is the key verdicts are cached under
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// verdictCache keeps the most recently used LLM verdicts for a limited time
+type verdictCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cachedVerdict struct {
+	key       string
+	verdict   *LLMModerationResponse
+	expiresAt time.Time
+}
+
+var llmVerdicts = newVerdictCache(
+	GetEnvInt("LLM_MODERATION_CACHE_SIZE", 10000),
+	time.Duration(GetEnvInt("LLM_MODERATION_CACHE_TTL_MINUTES", 24*60))*time.Minute,
+)
+
+func newVerdictCache(size int, ttl time.Duration) *verdictCache {
+	return &verdictCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *verdictCache) get(key string) (*LLMModerationResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cachedVerdict)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.verdict, true
+}
+
+func (c *verdictCache) put(key string, verdict *LLMModerationResponse) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+	}
+	c.entries[key] = c.order.PushFront(&cachedVerdict{
+		key:       key,
+		verdict:   verdict,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+
+	// Evict the least recently used verdicts
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedVerdict).key)
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @display_name tokens. Display names may contain letters, digits
+// and the punctuation Discord allows, such as LinusTech#1337.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.#-]{2,32})`)
+
+// ParseMentions returns the distinct display names mentioned in a message, lowercased,
+// in the order they first appear
+func ParseMentions(contents string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(contents, -1) {
+		name := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"No mentions", "Hello everyone!", nil},
+		{"Single mention", "@Linus what do you think?", []string{"linus"}},
+		{"Discriminator", "Nice one @LinusTech#1337.", []string{"linustech#1337"}},
+		{"Duplicates", "@luke @Luke @linus", []string{"luke", "linus"}},
+		{"Email address", "mail me at someone@example.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseMentions(tt.content)
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("ParseMentions(%q) = %v, expected %v", tt.content, result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,85 +1,153 @@
 package utils
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"log"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
-	"time"
+	"sync"
 )
 
-// ModerationResult represents the result of content moderation
+// ModerationResult is the verdict of a moderation stage. Rejections carry the stage and
+// rule that matched, so false positives can be traced back and tuned.
 type ModerationResult struct {
 	Allowed bool   `json:"allowed"`
 	Reason  string `json:"reason,omitempty"`
+	Stage   string `json:"stage,omitempty"`
+	RuleID  string `json:"rule_id,omitempty"`
+	Match   string `json:"match,omitempty"`
 }
 
-// Keyword-based moderation
-var bannedKeywords = []string{
-	// Common slurs and hateful terms
-	"nigger", "nigga", "coon", "chink", "gook", "spic", "wetback", "beaner",
-	"kike", "heeb", "raghead", "sandnigger", "towelhead", "paki", "currymuncher",
-	"jap", "slope", "zipperhead", "faggot", "fag", "homo", "queer", "tranny",
-	"cunt", "whore", "slut", "bitch", "pussy", "dick", "cock", "asshole",
-	"fuck", "shit", "bastard", "motherfucker", "cocksucker",
-	// Hateful terms
-	"racist", "nazi", "hitler", "supremacist", "terrorist", "pedophile",
-	"rapist", "murderer", "killer", "suicide", "kill yourself", "die",
-	// Common bypass attempts
-	"n1gger", "n1gga", "nigg3r", "nigg3r", "f4ggot", "f4g", "c0ck", "d1ck",
-	"f*ck", "sh*t", "b*tch", "c*nt", "p*ssy", "a**hole", "m*therf*cker",
-	// Leetspeak variations
-	"phaggot", "phag", "cocksuka", "cocksucker", "motherfuka", "motherfucker",
+// reject builds a rejection verdict for a rule
+func reject(ruleID string, reason string, match string) *ModerationResult {
+	return &ModerationResult{
+		Allowed: false,
+		Reason:  reason,
+		RuleID:  ruleID,
+		Match:   match,
+	}
+}
+
+// disabledModerationRules lists rule IDs that are never enforced, to switch off a rule that
+// causes false positives without a deploy
+var disabledModerationRules = parseList(os.Getenv("MODERATION_DISABLED_RULES"))
+
+// moderationRuleEnabled reports whether a rule is enforced
+func moderationRuleEnabled(ruleID string) bool {
+	return !slices.Contains(disabledModerationRules, ruleID)
+}
+
+// parseList splits a comma separated setting into its trimmed, non-empty entries
+func parseList(value string) []string {
+	var list []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// wordMatches reports whether a normalized word matches a banned word pattern
+func wordMatches(word string, pattern string) bool {
+	prefix := strings.HasSuffix(pattern, "*")
+	pattern = strings.TrimSuffix(pattern, "*")
+
+	matches := func(word, pattern string) bool {
+		return word == pattern || word == pluralize(pattern) || (prefix && strings.HasPrefix(word, pattern))
+	}
+	if matches(word, pattern) {
+		return true
+	}
+
+	// Letters stretched out to dodge the filter ("fuuuuck") are squeezed back together
+	return hasRepeatRun(word, 3) && matches(squeezeRepeats(word), squeezeRepeats(pattern))
+}
+
+// pluralize returns the regular English plural of a word
+func pluralize(word string) string {
+	for _, suffix := range []string{"s", "x", "z", "ch", "sh"} {
+		if strings.HasSuffix(word, suffix) {
+			return word + "es"
+		}
+	}
+	return word + "s"
+}
+
+// matchKeyword reports whether a banned word or phrase appears in normalized words
+func matchKeyword(words []string, keyword string) bool {
+	parts := strings.Fields(keyword)
+	for i := 0; i+len(parts) <= len(words); i++ {
+		matched := true
+		for j, part := range parts {
+			if !wordMatches(words[i+j], part) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
 }
 
-// CheckKeywordModeration performs keyword-based content moderation
+// CheckKeywordModeration rejects banned words, stretched out characters and shouting
 func CheckKeywordModeration(content string) *ModerationResult {
-	contentLower := strings.ToLower(content)
+	return checkKeywords(&ModerationInput{
+		Raw:        content,
+		Normalized: NormalizeContent(content),
+	})
+}
+
+func checkKeywords(input *ModerationInput) *ModerationResult {
+	rules := CurrentModerationRules()
+	content := input.Raw
+	words := strings.Fields(rules.removeAllowed(input.Normalized))
 
-	// Check for banned keywords (substring matching to catch variations)
-	for _, keyword := range bannedKeywords {
-		if strings.Contains(contentLower, keyword) {
-			return &ModerationResult{
-				Allowed: false,
+	for _, rule := range rules.Keywords {
+		if !moderationRuleEnabled(rule.ID) {
+			continue
+		}
+		for _, keyword := range rule.Words {
+			if matchKeyword(words, keyword) {
+				return reject(rule.ID, rule.Reason, keyword)
 			}
 		}
 	}
 
-	// Check for repeated characters (potential bypass)
-	if hasRepeatedChars(content) {
-		return &ModerationResult{
-			Allowed: false,
+	for _, rule := range rules.Regexes {
+		if !moderationRuleEnabled(rule.ID) {
+			continue
+		}
+		if rule.Pattern.MatchString(content) {
+			return reject(rule.ID, rule.Reason, rule.Pattern.String())
 		}
 	}
 
+	// Check for repeated characters (potential bypass)
+	if moderationRuleEnabled("spam.repeated_chars") && hasRepeatedChars(content, rules.RepeatLimit) {
+		return reject("spam.repeated_chars", "Too many repeated characters", "")
+	}
+
 	// Check for excessive caps
-	if hasExcessiveCaps(content) {
-		return &ModerationResult{
-			Allowed: false,
-		}
+	if moderationRuleEnabled("spam.caps") && hasExcessiveCaps(content, rules.CapsMinLength, rules.CapsRatio) {
+		return reject("spam.caps", "Too many capital letters", "")
 	}
 
 	return &ModerationResult{Allowed: true}
 }
 
-// hasRepeatedChars checks for suspicious repeated characters
-func hasRepeatedChars(content string) bool {
-	// Check for 5 or more consecutive identical characters
-	for i := 0; i < len(content)-4; i++ {
-		if content[i] == content[i+1] && content[i] == content[i+2] &&
-			content[i] == content[i+3] && content[i] == content[i+4] {
-			return true
-		}
-	}
-	return false
+// hasRepeatedChars checks for the same character repeated limit or more times in a row
+func hasRepeatedChars(content string, limit int) bool {
+	return limit >= 2 && hasRepeatRun(content, limit)
 }
 
-// hasExcessiveCaps checks for excessive capitalization
-func hasExcessiveCaps(content string) bool {
-	if len(content) < 10 {
+// hasExcessiveCaps checks whether more than ratio of the letters in a message of at least
+// minLength characters are capitals
+func hasExcessiveCaps(content string, minLength int, ratio float64) bool {
+	if len(content) < minLength {
 		return false
 	}
 
@@ -99,63 +167,7 @@ func hasExcessiveCaps(content string) bool {
 		return false
 	}
 
-	return float64(capsCount)/float64(totalLetters) > 0.8 // 80% caps
-}
-
-// LLMModerationRequest represents a request to the LLM moderation service
-type LLMModerationRequest struct {
-	Content string `json:"content"`
-}
-
-// LLMModerationResponse represents the response from the LLM moderation service
-type LLMModerationResponse struct {
-	Toxic      bool    `json:"toxic"`
-	Confidence float64 `json:"confidence"`
-	Reason     string  `json:"reason,omitempty"`
-}
-
-// CheckLLMModeration performs LLM-based content moderation
-func CheckLLMModeration(content string) *ModerationResult {
-	llmEndpoint := os.Getenv("LLM_MODERATION_ENDPOINT")
-	if llmEndpoint == "" {
-		// If no LLM endpoint configured, skip LLM check
-		return &ModerationResult{Allowed: true}
-	}
-
-	reqBody := LLMModerationRequest{Content: content}
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		Debugf("Failed to marshal LLM request: %v", err)
-		return &ModerationResult{Allowed: true} // Allow on error
-	}
-
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Post(llmEndpoint, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		Debugf("LLM moderation request failed: %v", err)
-		return &ModerationResult{Allowed: true} // Allow on error
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		Debugf("LLM moderation returned status %d", resp.StatusCode)
-		return &ModerationResult{Allowed: true} // Allow on error
-	}
-
-	var llmResp LLMModerationResponse
-	if err := json.NewDecoder(resp.Body).Decode(&llmResp); err != nil {
-		Debugf("Failed to decode LLM response: %v", err)
-		return &ModerationResult{Allowed: true} // Allow on error
-	}
-
-	if llmResp.Toxic && llmResp.Confidence > 0.7 { // 70% confidence threshold
-		return &ModerationResult{
-			Allowed: false,
-			Reason:  fmt.Sprintf("LLM detected toxic content: %s", llmResp.Reason),
-		}
-	}
-
-	return &ModerationResult{Allowed: true}
+	return float64(capsCount)/float64(totalLetters) > ratio
 }
 
 // CheckMarkdownModeration filters out non-inline markdown content
@@ -167,77 +179,50 @@ func CheckMarkdownModeration(content string) *ModerationResult {
 
 		// Reject headers (# ## ### etc.)
 		if strings.HasPrefix(line, "#") {
-			return &ModerationResult{
-				Allowed: false,
-				Reason:  "Headers are not allowed",
-			}
+			return reject("markdown.header", "Headers are not allowed", "")
 		}
 
 		// Reject blockquotes (> at start of line)
 		if strings.HasPrefix(line, ">") {
-			return &ModerationResult{
-				Allowed: false,
-				Reason:  "Blockquotes are not allowed",
-			}
+			return reject("markdown.blockquote", "Blockquotes are not allowed", "")
 		}
 
 		// Reject unordered lists (- or * at start of line)
 		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
-			return &ModerationResult{
-				Allowed: false,
-				Reason:  "Lists are not allowed",
-			}
+			return reject("markdown.list", "Lists are not allowed", "")
 		}
 
 		// Reject ordered lists (1. 2. etc.)
 		orderedListPattern := regexp.MustCompile(`^\d+\.\s`)
 		if orderedListPattern.MatchString(line) {
-			return &ModerationResult{
-				Allowed: false,
-				Reason:  "Ordered lists are not allowed",
-			}
+			return reject("markdown.ordered_list", "Ordered lists are not allowed", "")
 		}
 
 		// Reject horizontal rules (--- or *** or ___)
 		if line == "---" || line == "***" || line == "___" {
-			return &ModerationResult{
-				Allowed: false,
-				Reason:  "Horizontal rules are not allowed",
-			}
+			return reject("markdown.horizontal_rule", "Horizontal rules are not allowed", "")
 		}
 
 		// Reject table rows (containing | and potentially - for separators)
 		if strings.Contains(line, "|") && (strings.Contains(line, "---") || strings.Contains(line, ":-") || strings.Contains(line, "-:")) {
-			return &ModerationResult{
-				Allowed: false,
-				Reason:  "Tables are not allowed",
-			}
+			return reject("markdown.table", "Tables are not allowed", "")
 		}
 	}
 
 	// Reject code blocks (```)
 	if strings.Contains(content, "```") {
-		return &ModerationResult{
-			Allowed: false,
-			Reason:  "Code blocks are not allowed",
-		}
+		return reject("markdown.code_block", "Code blocks are not allowed", "")
 	}
 
 	// Reject images (![alt](url) or ![alt][ref])
 	imagePattern := regexp.MustCompile(`!\[.*?\]\(.*?\)`)
 	if imagePattern.MatchString(content) {
-		return &ModerationResult{
-			Allowed: false,
-			Reason:  "Images are not allowed",
-		}
+		return reject("markdown.image", "Images are not allowed", "")
 	}
 
 	imageRefPattern := regexp.MustCompile(`!\[.*?\]\[.*?\]`)
 	if imageRefPattern.MatchString(content) {
-		return &ModerationResult{
-			Allowed: false,
-			Reason:  "Images are not allowed",
-		}
+		return reject("markdown.image", "Images are not allowed", "")
 	}
 
 	// Allow links - [text](url) and [text][ref] are OK
@@ -247,31 +232,131 @@ func CheckMarkdownModeration(content string) *ModerationResult {
 	return &ModerationResult{Allowed: true}
 }
 
-// ModerateContent performs comprehensive content moderation
-func ModerateContent(content string) *ModerationResult {
-	// First, check markdown formatting
-	markdownResult := CheckMarkdownModeration(content)
-	if !markdownResult.Allowed {
-		return markdownResult
-	}
+// ModerationInput is a message prepared for the moderation stages
+type ModerationInput struct {
+	// Raw is the message as sent, for checks that care about formatting and case
+	Raw string
+	// Normalized is the message run through NormalizeContent
+	Normalized string
+}
 
-	// Then, check keywords
-	keywordResult := CheckKeywordModeration(content)
-	if !keywordResult.Allowed {
-		return &ModerationResult{
-			Allowed: false,
-			Reason:  "Content contains banned keywords or patterns",
+// Moderator is a single stage of the moderation pipeline
+type Moderator interface {
+	Name() string
+	Moderate(input *ModerationInput) *ModerationResult
+}
+
+// moderatorFunc adapts a function into a Moderator
+type moderatorFunc struct {
+	name     string
+	moderate func(input *ModerationInput) *ModerationResult
+}
+
+func (m moderatorFunc) Name() string {
+	return m.name
+}
+
+func (m moderatorFunc) Moderate(input *ModerationInput) *ModerationResult {
+	return m.moderate(input)
+}
+
+// moderators holds every stage that can be named in MODERATION_STAGES
+var moderators = map[string]Moderator{}
+
+// RegisterModerator makes a moderation stage available to the pipeline under its name
+func RegisterModerator(m Moderator) {
+	moderators[m.Name()] = m
+}
+
+func init() {
+	RegisterModerator(moderatorFunc{name: "markdown", moderate: func(input *ModerationInput) *ModerationResult {
+		return CheckMarkdownModeration(input.Raw)
+	}})
+	RegisterModerator(moderatorFunc{name: "keywords", moderate: checkKeywords})
+	RegisterModerator(moderatorFunc{name: "llm", moderate: func(input *ModerationInput) *ModerationResult {
+		return CheckLLMModeration(input.Raw)
+	}})
+}
+
+// defaultModerationStages is the pipeline used when MODERATION_STAGES is not set. The LLM
+// check is left out as it runs in the background after messages are published.
+const defaultModerationStages = "markdown,keywords"
+
+// ModerationPipeline runs messages through moderation stages in order, stopping at the
+// first stage that rejects them
+type ModerationPipeline struct {
+	stages []Moderator
+}
+
+// NewModerationPipeline builds a pipeline from stages
+func NewModerationPipeline(stages ...Moderator) *ModerationPipeline {
+	return &ModerationPipeline{stages: stages}
+}
+
+// NewModerationPipelineFromNames builds a pipeline from registered stage names, skipping
+// names that are not registered
+func NewModerationPipelineFromNames(names []string) *ModerationPipeline {
+	stages := make([]Moderator, 0, len(names))
+	for _, name := range names {
+		stage, ok := moderators[name]
+		if !ok {
+			log.Printf("[Moderation] Unknown moderation stage %q, skipping", name)
+			continue
 		}
+		stages = append(stages, stage)
 	}
+	return NewModerationPipeline(stages...)
+}
 
-	// Only check with LLM if endpoint is configured
-	llmEndpoint := os.Getenv("LLM_MODERATION_ENDPOINT")
-	if llmEndpoint != "" {
-		llmResult := CheckLLMModeration(content)
-		if !llmResult.Allowed {
-			return llmResult
+// HasStage reports whether the pipeline runs the named stage
+func (p *ModerationPipeline) HasStage(name string) bool {
+	return slices.ContainsFunc(p.stages, func(stage Moderator) bool {
+		return stage.Name() == name
+	})
+}
+
+// Moderate normalizes a message once and runs it through every stage
+func (p *ModerationPipeline) Moderate(content string) *ModerationResult {
+	input := &ModerationInput{
+		Raw:        content,
+		Normalized: NormalizeContent(content),
+	}
+
+	for _, stage := range p.stages {
+		result := stage.Moderate(input)
+		if result.Allowed {
+			continue
 		}
+		if result.RuleID != "" && !moderationRuleEnabled(result.RuleID) {
+			Debugf("[Moderation] Ignoring disabled rule %s from stage %s", result.RuleID, stage.Name())
+			continue
+		}
+		result.Stage = stage.Name()
+		notifyModerationHit(result)
+		return result
 	}
 
 	return &ModerationResult{Allowed: true}
 }
+
+var (
+	defaultPipeline     *ModerationPipeline
+	defaultPipelineOnce sync.Once
+)
+
+// DefaultModerationPipeline returns the pipeline configured by MODERATION_STAGES
+func DefaultModerationPipeline() *ModerationPipeline {
+	defaultPipelineOnce.Do(func() {
+		stages := os.Getenv("MODERATION_STAGES")
+		if stages == "" {
+			stages = defaultModerationStages
+		}
+		defaultPipeline = NewModerationPipelineFromNames(parseList(stages))
+	})
+	return defaultPipeline
+}
+
+// ModerateContent runs a message through the configured moderation pipeline
+func ModerateContent(content string) *ModerationResult {
+	return DefaultModerationPipeline().Moderate(content)
+}
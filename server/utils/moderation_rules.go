@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// KeywordRule is a group of banned words sharing a rule ID. Words are matched on word
+// boundaries against normalized content, plurals included. A trailing * also matches any
+// word starting with it, and words may span several words ("kill yourself").
+type KeywordRule struct {
+	ID     string
+	Reason string
+	Words  []string
+}
+
+// RegexRule rejects messages whose raw contents match a case-insensitive pattern
+type RegexRule struct {
+	ID      string
+	Reason  string
+	Pattern *regexp.Regexp
+}
+
+// ModerationRules is everything the keyword stage matches messages against
+type ModerationRules struct {
+	Keywords []KeywordRule
+	Regexes  []RegexRule
+	// Allowed are normalized phrases removed from a message before keywords are matched
+	Allowed []string
+	// CapsRatio is the share of capital letters above which a message is shouting
+	CapsRatio float64
+	// CapsMinLength is the shortest message checked for shouting
+	CapsMinLength int
+	// RepeatLimit is how many times in a row a character may appear
+	RepeatLimit int
+}
+
+// DefaultModerationRules returns the built-in rules, used until rules are loaded from the
+// database
+func DefaultModerationRules() *ModerationRules {
+	return &ModerationRules{
+		Keywords: []KeywordRule{
+			{
+				ID:     "keyword.slur",
+				Reason: "Slurs are not allowed",
+				Words: []string{
+					"nigger", "nigga", "sandnigger", "coon", "chink", "gook", "spic", "wetback", "beaner",
+					"kike", "heeb", "raghead", "towelhead", "paki", "currymuncher", "jap", "slope",
+					"zipperhead", "faggot", "fag", "phaggot", "phag", "homo", "queer", "tranny",
+				},
+			},
+			{
+				ID:     "keyword.profanity",
+				Reason: "Profanity is not allowed",
+				Words: []string{
+					"fuck*", "motherfuck*", "motherfuk*", "shit*", "cunt*", "whore*", "slut*", "bitch*",
+					"pussy", "pussies", "dick", "cock", "cocksuck*", "cocksuk*", "asshole", "bastard",
+				},
+			},
+			{
+				ID:     "keyword.hate",
+				Reason: "Hateful language is not allowed",
+				Words: []string{
+					"racist", "nazi", "hitler", "supremacist", "terrorist", "pedophile", "rapist",
+				},
+			},
+			{
+				ID:     "keyword.violence",
+				Reason: "Violent language is not allowed",
+				Words: []string{
+					"murderer", "killer", "suicide", "kill yourself", "kys", "go die", "die in a fire",
+				},
+			},
+		},
+		CapsRatio:     0.8,
+		CapsMinLength: 10,
+		RepeatLimit:   5,
+	}
+}
+
+var activeModerationRules atomic.Pointer[ModerationRules]
+
+func init() {
+	activeModerationRules.Store(DefaultModerationRules())
+}
+
+// SetModerationRules swaps the rules used by the moderation pipeline. Messages being
+// moderated at the time finish with the rules they started with.
+func SetModerationRules(rules *ModerationRules) {
+	activeModerationRules.Store(rules)
+}
+
+// CurrentModerationRules returns the rules the moderation pipeline is using
+func CurrentModerationRules() *ModerationRules {
+	return activeModerationRules.Load()
+}
+
+// removeAllowed blanks out allowlisted phrases from normalized content
+func (r *ModerationRules) removeAllowed(normalized string) string {
+	if len(r.Allowed) == 0 {
+		return normalized
+	}
+
+	padded := " " + normalized + " "
+	for _, phrase := range r.Allowed {
+		// Replace until nothing changes, as neighbouring occurrences share a space
+		for {
+			replaced := strings.ReplaceAll(padded, " "+phrase+" ", "  ")
+			if replaced == padded {
+				break
+			}
+			padded = replaced
+		}
+	}
+	return padded
+}
+
+// ModerationHitListener is told about every message rejected by the pipeline
+type ModerationHitListener func(result *ModerationResult)
+
+var moderationHitListeners []ModerationHitListener
+
+// OnModerationHit registers a listener called whenever the pipeline rejects a message
+func OnModerationHit(listener ModerationHitListener) {
+	moderationHitListeners = append(moderationHitListeners, listener)
+}
+
+// notifyModerationHit informs the registered listeners about a rejected message
+func notifyModerationHit(result *ModerationResult) {
+	for _, listener := range moderationHitListeners {
+		listener(result)
+	}
+}
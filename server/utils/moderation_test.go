@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"regexp"
 	"testing"
 )
 
@@ -18,6 +19,24 @@ func TestCheckKeywordModeration(t *testing.T) {
 		{"Excessive caps", "THIS IS ALL CAPS AND VERY LOUD", false},
 		{"Normal caps", "This is a normal message", true},
 		{"Short caps", "HI", true},
+		{"Banned word inside a word", "Indie games on a diet", true},
+		{"Banned word as a prefix", "A homogeneous mixture", true},
+		{"Plural of a banned word", "Those dicks", false},
+		{"Leetspeak symbols", "What a load of $h!t", false},
+		{"Spaced out letters", "f u c k this", false},
+		{"Dotted letters", "f.u.c.k this", false},
+		{"Zero width characters", "fu\u200bck this", false},
+		{"Cyrillic confusables", "fuсk this", false},
+		{"Fullwidth letters", "ｆｕｃｋ this", false},
+		{"Stretched letters", "fuuuck this", false},
+		{"Banned phrase", "just kill yourself", false},
+		{"Punctuation after a word", "Well, shit!", false},
+		{"Die as a figure of speech", "I'd die laughing", true},
+		{"Die in a title", "Die Hard is a Christmas movie", true},
+		{"Dice notation", "roll a d13", true},
+		{"Plain number", "1337", true},
+		{"Apostrophe before a single letter", "that's a good point", true},
+		{"Violent phrase", "just go die", false},
 	}
 
 	for _, tt := range tests {
@@ -30,6 +49,57 @@ func TestCheckKeywordModeration(t *testing.T) {
 	}
 }
 
+func TestNormalizeContent(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"Hello, World!", "hello world"},
+		{"n1gg3r", "nigger"},
+		{"$h!t", "shit"},
+		{"f u c k", "fuck"},
+		{"b\u200bad", "bad"},
+		{"саt", "cat"},
+		{"ｆｕｌｌ", "full"},
+		{"café", "cafe"},
+		{"cafe\u0301", "cafe"},
+		{"1337 in 2024", "in"},
+		{"h4x0r", "haxor"},
+		{"that's a", "thats a"},
+		{"I’d say", "id say"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			if got := NormalizeContent(tt.content); got != tt.expected {
+				t.Errorf("NormalizeContent(%q) = %q, expected %q", tt.content, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModerationPipelineRuleIDs(t *testing.T) {
+	tests := []struct {
+		content string
+		stage   string
+		ruleID  string
+	}{
+		{"# Header", "markdown", "markdown.header"},
+		{"That guy is a n1gger", "keywords", "keyword.slur"},
+		{"THIS IS ALL CAPS AND VERY LOUD", "keywords", "spam.caps"},
+	}
+
+	pipeline := NewModerationPipelineFromNames([]string{"markdown", "keywords"})
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			result := pipeline.Moderate(tt.content)
+			if result.Allowed || result.Stage != tt.stage || result.RuleID != tt.ruleID {
+				t.Errorf("Moderate(%q) = %+v, expected stage %s and rule %s", tt.content, result, tt.stage, tt.ruleID)
+			}
+		})
+	}
+}
+
 func TestCheckMarkdownModeration(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -88,3 +158,33 @@ func TestModerateContent(t *testing.T) {
 		})
 	}
 }
+
+func TestModerationRules(t *testing.T) {
+	defer SetModerationRules(CurrentModerationRules())
+
+	rules := DefaultModerationRules()
+	rules.Allowed = []string{"homo sapiens"}
+	rules.Regexes = []RegexRule{{ID: "regex.invite", Pattern: regexp.MustCompile(`(?i)discord\.gg/\w+`)}}
+	rules.RepeatLimit = 3
+	SetModerationRules(rules)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"Allowlisted phrase", "We are homo sapiens", true},
+		{"Term outside allowlisted phrase", "homo", false},
+		{"Regex rule", "Join DISCORD.GG/abc123", false},
+		{"Lowered repeat limit", "Hellooo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckKeywordModeration(tt.content)
+			if result.Allowed != tt.expected {
+				t.Errorf("CheckKeywordModeration(%q) = %+v, expected %v", tt.content, result, tt.expected)
+			}
+		})
+	}
+}
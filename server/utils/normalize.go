@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// invisibleRunes are characters that render as nothing and are used to split up words
+var invisibleRunes = map[rune]bool{
+	'\u00ad': true, // soft hyphen
+	'\u180e': true, // mongolian vowel separator
+	'\u200b': true, // zero width space
+	'\u200c': true, // zero width non-joiner
+	'\u200d': true, // zero width joiner
+	'\u2060': true, // word joiner
+	'\ufeff': true, // zero width no-break space
+}
+
+// confusables maps lowercase letters that look like latin letters to the letter they imitate
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i', 'ї': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd',
+	'ո': 'n', 'ԛ': 'q', 'ԝ': 'w',
+	// Greek
+	'α': 'a', 'β': 'b', 'γ': 'y', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w',
+	// Accented latin
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ā': 'a', 'ă': 'a', 'ą': 'a',
+	'ç': 'c', 'ć': 'c', 'č': 'c', 'ď': 'd', 'đ': 'd',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e', 'ė': 'e', 'ę': 'e', 'ě': 'e',
+	'ğ': 'g', 'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ī': 'i', 'ı': 'i', 'ł': 'l',
+	'ñ': 'n', 'ń': 'n', 'ň': 'n', 'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o', 'ō': 'o',
+	'ř': 'r', 'ś': 's', 'š': 's', 'ş': 's', 'ť': 't', 'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ū': 'u',
+	'ů': 'u', 'ý': 'y', 'ÿ': 'y', 'ź': 'z', 'ż': 'z', 'ž': 'z',
+	// Other lookalikes
+	'ɡ': 'g', 'ʀ': 'r', 'ѵ': 'v', 'ɑ': 'a',
+}
+
+// leetDigits maps digits to the letters they stand in for in leetspeak, only when they touch a
+// letter so that plain numbers stay numbers
+var leetDigits = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+}
+
+// leetSymbols maps symbols to the letters they stand in for, only inside words so that
+// punctuation at the end of a sentence stays punctuation
+var leetSymbols = map[rune]rune{
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', '+': 't',
+}
+
+// foldRune maps a single lowercase rune to its plain latin equivalent
+func foldRune(r rune) rune {
+	// Fullwidth forms sit at a fixed offset from ASCII
+	if r >= '\uff01' && r <= '\uff5e' {
+		r -= 0xfee0
+	}
+	if folded, ok := confusables[r]; ok {
+		return folded
+	}
+	return r
+}
+
+// isApostrophe reports whether a rune is a straight or curly apostrophe
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// NormalizeContent reduces a message to lowercase latin words separated by single spaces,
+// undoing the tricks used to slip words past a filter: confusable unicode letters, leetspeak,
+// zero-width characters, combining marks and letters split up by spaces or punctuation.
+// Apostrophes inside a word are dropped, so "that's" reads as "thats" rather than "that s".
+func NormalizeContent(content string) string {
+	runes := make([]rune, 0, len(content))
+	for _, r := range content {
+		if invisibleRunes[r] || unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		runes = append(runes, unicode.ToLower(r))
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		if folded, ok := leetSymbols[r]; ok && i+1 < len(runes) && isWordRune(runes[i+1]) {
+			b.WriteRune(folded)
+			continue
+		}
+
+		r = foldRune(r)
+		if folded, ok := leetDigits[r]; ok && digitsTouchLetter(runes, i) {
+			b.WriteRune(folded)
+			continue
+		}
+		if isApostrophe(r) && i > 0 && i+1 < len(runes) && isWordRune(runes[i-1]) && isWordRune(runes[i+1]) {
+			continue
+		}
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(joinSingleLetters(strings.Fields(b.String())), " ")
+}
+
+// isWordRune reports whether a rune can be part of a word once folded
+func isWordRune(r rune) bool {
+	r = foldRune(r)
+	_, digit := leetDigits[r]
+	return isLetter(r) || digit
+}
+
+// isLetter reports whether a folded rune is a plain latin letter
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z'
+}
+
+// digitsTouchLetter reports whether the run of digits around runes[i] has a letter directly
+// before or after it, so "sh1t" and "n1gg3r" fold while "1337" and "2024" do not
+func digitsTouchLetter(runes []rune, i int) bool {
+	isDigit := func(r rune) bool {
+		r = foldRune(r)
+		return r >= '0' && r <= '9'
+	}
+	start, end := i, i
+	for start > 0 && isDigit(runes[start-1]) {
+		start--
+	}
+	for end+1 < len(runes) && isDigit(runes[end+1]) {
+		end++
+	}
+	return (start > 0 && isLetter(foldRune(runes[start-1]))) ||
+		(end+1 < len(runes) && isLetter(foldRune(runes[end+1])))
+}
+
+// joinSingleLetters glues runs of single letter words back together, so "f u c k" and
+// "f.u.c.k" are read as one word
+func joinSingleLetters(words []string) []string {
+	joined := make([]string, 0, len(words))
+	run := ""
+	for _, word := range words {
+		if len(word) == 1 {
+			run += word
+			continue
+		}
+		if run != "" {
+			joined = append(joined, run)
+			run = ""
+		}
+		joined = append(joined, word)
+	}
+	if run != "" {
+		joined = append(joined, run)
+	}
+	return joined
+}
+
+// squeezeRepeats collapses runs of the same letter, so "fuuuuck" reads as "fuck"
+func squeezeRepeats(word string) string {
+	var b strings.Builder
+	var last rune
+	for i, r := range word {
+		if i > 0 && r == last {
+			continue
+		}
+		b.WriteRune(r)
+		last = r
+	}
+	return b.String()
+}
+
+// hasRepeatRun reports whether a word repeats the same letter at least n times in a row
+func hasRepeatRun(word string, n int) bool {
+	count := 0
+	var last rune
+	for _, r := range word {
+		if r == last {
+			count++
+		} else {
+			count = 1
+		}
+		if count >= n {
+			return true
+		}
+		last = r
+	}
+	return false
+}
+
+// NormalizeTerm normalizes a banned word pattern the same way as messages, keeping the
+// trailing * that makes it match any word starting with it
+func NormalizeTerm(term string) string {
+	term = strings.TrimSpace(term)
+	if strings.HasSuffix(term, "*") {
+		return NormalizeContent(strings.TrimSuffix(term, "*")) + "*"
+	}
+	return NormalizeContent(term)
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneThreshold is the number of tracked keys above which idle buckets are dropped
+const pruneThreshold = 10000
+
+// tokenBucket tracks the tokens left for a single key
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter is an in-memory token bucket limiter keyed by an arbitrary string,
+// such as a player ID. Each key may burst up to capacity requests, then refills
+// at a steady rate.
+type RateLimiter struct {
+	mu       sync.Mutex
+	buckets  map[string]*tokenBucket
+	capacity float64
+	rate     float64 // tokens per second
+	now      func() time.Time
+}
+
+// NewRateLimiter creates a limiter allowing bursts of capacity requests, refilling perMinute tokens a minute
+func NewRateLimiter(capacity int, perMinute int) *RateLimiter {
+	return &RateLimiter{
+		buckets:  make(map[string]*tokenBucket),
+		capacity: float64(capacity),
+		rate:     float64(perMinute) / 60,
+		now:      time.Now,
+	}
+}
+
+// Allow takes a token for key. If none are left it returns false and how long
+// to wait until the next token is available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= pruneThreshold {
+			l.prune(now)
+		}
+		b = &tokenBucket{tokens: l.capacity, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.capacity, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	if l.rate <= 0 {
+		return false, time.Minute
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// prune drops the buckets which have refilled completely, as they behave the same as new ones
+func (l *RateLimiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
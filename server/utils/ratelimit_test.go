@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewRateLimiter(3, 60)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.Allow("player"); !ok {
+			t.Fatalf("request %d was limited within the burst", i+1)
+		}
+	}
+
+	ok, retryAfter := limiter.Allow("player")
+	if ok {
+		t.Fatal("request beyond the burst was allowed")
+	}
+	if retryAfter != time.Second {
+		t.Fatalf("retryAfter = %v, expected %v", retryAfter, time.Second)
+	}
+
+	if ok, _ := limiter.Allow("other"); !ok {
+		t.Fatal("a different key was limited")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := limiter.Allow("player"); !ok {
+		t.Fatal("request was limited after a token refilled")
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"wanshow-bingo/middleware"
 
@@ -44,6 +45,15 @@ func init() {
 
 }
 
+// GetEnvInt reads an integer environment variable, falling back to def when it is unset or invalid
+func GetEnvInt(key string, def int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
 func RegisterRouter(path string, callback func(c fiber.Router)) {
 	router := app.Group(path)
 	callback(router)
@@ -18,6 +18,9 @@ import (
 
 var AggregateChan chan *whenplane.Aggregate
 
+// wanShowTileID is the "4 Hour WAN Show" tile confirmed when the WAN Show Timer expires
+const wanShowTileID = "BfaqFYztlR"
+
 func init() {
 	AggregateChan = make(chan *whenplane.Aggregate, 100)
 	AggregateHandler()
@@ -446,7 +449,16 @@ func UpdateShowState(ctx context.Context, aggregate *whenplane.Aggregate) error
 				StartsAt:  &now,
 				ExpiresAt: &expiresAt,
 				IsActive:  true,
-				Settings:  map[string]interface{}{},
+				Settings: map[string]interface{}{
+					// Confirm the 4-hour WAN show tile if the show is still live when the timer expires
+					"actions": []models.TimerAction{
+						{
+							Type:    models.TimerActionConfirmTile,
+							TileID:  wanShowTileID,
+							Context: "Automatic confirmation after 4-hour timer",
+						},
+					},
+				},
 				CreatedAt: now,
 				UpdatedAt: now,
 			}
@@ -455,14 +467,18 @@ func UpdateShowState(ctx context.Context, aggregate *whenplane.Aggregate) error
 				log.Printf("[AGGREGATE] Failed to create WAN timer: %v", err)
 			} else {
 				log.Printf("[AGGREGATE] Created WAN timer for show %s", latestShow.ID)
+				sse.BroadcastTimerEvent("timer.created", timer, timer)
 			}
 		} else if newState != models.ShowStateLive && latestShow.State == models.ShowStateLive {
 			// Show went offline, cancel WAN timer
-			err = db.StopActiveTimersByTitle(context.Background(), "WAN Show Timer", latestShow.ID)
+			stopped, err := db.StopActiveTimersByTitle(context.Background(), "WAN Show Timer", latestShow.ID)
 			if err != nil {
 				log.Printf("[AGGREGATE] Failed to stop WAN timer: %v", err)
 			} else {
 				log.Printf("[AGGREGATE] Stopped WAN timer for show %s", latestShow.ID)
+				for i := range stopped {
+					sse.BroadcastTimerEvent("timer.stopped", &stopped[i], &stopped[i])
+				}
 			}
 		}
 	}